@@ -0,0 +1,284 @@
+package errors
+
+import (
+	e "errors"
+	"log/slog"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/lcnascimento/go-kit/errors/errorspb"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative errorspb/errors.proto
+
+var jsonMarshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// MarshalJSON encodes the CustomError tree into its canonical JSON wire format.
+// See [ToProto] for details on what is encoded.
+func (ce custom) MarshalJSON() ([]byte, error) {
+	return jsonMarshalOptions.Marshal(ToProto(ce))
+}
+
+// UnmarshalJSON decodes the CustomError tree from its canonical JSON wire format.
+func (ce *custom) UnmarshalJSON(data []byte) error {
+	msg := &errorspb.Error{}
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return err
+	}
+
+	decoded, _ := FromProto(msg).(custom) //nolint:errcheck // FromProto always returns a custom error.
+	*ce = decoded
+
+	return nil
+}
+
+// FromJSON decodes a CustomError tree from its canonical JSON wire format.
+func FromJSON(data []byte) (CustomError, error) {
+	ce := &custom{}
+	if err := ce.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return *ce, nil
+}
+
+// Envelope holds a CustomError decoded from its canonical JSON wire format. It allows decoding CustomErrors
+// into struct fields, which can not be done into the CustomError interface itself:
+//
+//	var payload struct {
+//		Error errors.Envelope `json:"error"`
+//	}
+//
+// A nil CustomError is encoded as JSON null, and JSON null is decoded as a nil CustomError.
+type Envelope struct {
+	CustomError
+}
+
+// MarshalJSON encodes the held CustomError into its canonical JSON wire format.
+func (en Envelope) MarshalJSON() ([]byte, error) {
+	if en.CustomError == nil {
+		return []byte("null"), nil
+	}
+
+	return en.CustomError.MarshalJSON()
+}
+
+// UnmarshalJSON decodes the held CustomError from its canonical JSON wire format.
+func (en *Envelope) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		en.CustomError = nil
+		return nil
+	}
+
+	ce, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+
+	en.CustomError = ce
+
+	return nil
+}
+
+// ToProto encodes the given error tree into its canonical protobuf wire format.
+// Kind, Code, Retryable, typed attributes and the whole causes tree are encoded.
+// Native Go errors in the tree are encoded with their message and causes only.
+// Stack traces are not encoded, since they are only meaningful within the process that captured them.
+func ToProto(err error) *errorspb.Error {
+	if err == nil {
+		return nil
+	}
+
+	//nolint:errorlint // we don't want to use [errors.As] here intentionally.
+	ce, ok := err.(custom)
+	if !ok {
+		return &errorspb.Error{
+			Message: err.Error(),
+			Causes:  causesToProto(Unwrap(err)),
+		}
+	}
+
+	msg := &errorspb.Error{
+		Message:    ce.Error(),
+		Kind:       string(ce.kind),
+		Code:       string(ce.code),
+		Retryable:  ce.retryable,
+		Attributes: attrsToProto(ce.attrs),
+	}
+
+	if len(ce.errs) > 1 {
+		msg.Causes = causesToProto(ce.errs[1:])
+	}
+
+	return msg
+}
+
+// ToSafeProto encodes the given error tree like [ToProto], but leaves the native Go errors of its causes out,
+// to prevent exposing internal details, as [SafeReasons] does. The CustomErrors they wrap take their place.
+// It is meant for errors sent to clients outside the system.
+func ToSafeProto(err error) *errorspb.Error {
+	msg := ToProto(err)
+	if msg != nil {
+		msg.Causes = safeCauses(msg.GetCauses())
+	}
+
+	return msg
+}
+
+func safeCauses(causes []*errorspb.Error) []*errorspb.Error {
+	out := make([]*errorspb.Error, 0, len(causes))
+
+	for _, cause := range causes {
+		cause.Causes = safeCauses(cause.GetCauses())
+
+		if cause.GetKind() == "" {
+			out = append(out, cause.GetCauses()...)
+			continue
+		}
+
+		out = append(out, cause)
+	}
+
+	return out
+}
+
+// FromProto decodes an error tree from its canonical protobuf wire format.
+// The root of the tree is always decoded as a CustomError, even if it was encoded from a native Go error.
+// Inner native Go errors are decoded as errors that hold the same message and causes.
+func FromProto(msg *errorspb.Error) CustomError {
+	ce := custom{
+		kind:      KindUnknown,
+		code:      CodeUnknown,
+		attrs:     attrsFromProto(msg.GetAttributes()),
+		retryable: msg.GetRetryable(),
+		errs:      []error{e.New(msg.GetMessage())},
+	}
+
+	if msg.GetKind() != "" {
+		ce.kind = KindType(msg.GetKind())
+	}
+
+	if msg.GetCode() != "" {
+		ce.code = CodeType(msg.GetCode())
+	}
+
+	for _, cause := range msg.GetCauses() {
+		ce.errs = append(ce.errs, errorFromProto(cause))
+	}
+
+	return ce
+}
+
+func errorFromProto(msg *errorspb.Error) error {
+	if msg.GetKind() != "" {
+		return FromProto(msg)
+	}
+
+	decoded := decodedError{msg: msg.GetMessage()}
+	for _, cause := range msg.GetCauses() {
+		decoded.errs = append(decoded.errs, errorFromProto(cause))
+	}
+
+	return decoded
+}
+
+func causesToProto(errs []error) []*errorspb.Error {
+	causes := make([]*errorspb.Error, 0, len(errs))
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		causes = append(causes, ToProto(err))
+	}
+
+	return causes
+}
+
+//nolint:gosec // OK with overflow
+func attrsToProto(attrs []Attr) []*errorspb.Attribute {
+	out := make([]*errorspb.Attribute, 0, len(attrs))
+
+	for _, attr := range attrs {
+		pAttr := &errorspb.Attribute{Key: attr.Key}
+		value := attr.Value.Resolve()
+
+		switch value.Kind() {
+		case slog.KindInt64:
+			pAttr.Value = &errorspb.Attribute_IntValue{IntValue: value.Int64()}
+		case slog.KindUint64:
+			pAttr.Value = &errorspb.Attribute_UintValue{UintValue: value.Uint64()}
+		case slog.KindFloat64:
+			pAttr.Value = &errorspb.Attribute_FloatValue{FloatValue: value.Float64()}
+		case slog.KindBool:
+			pAttr.Value = &errorspb.Attribute_BoolValue{BoolValue: value.Bool()}
+		case slog.KindTime:
+			pAttr.Value = &errorspb.Attribute_TimeValue{TimeValue: timestamppb.New(value.Time())}
+		case slog.KindDuration:
+			pAttr.Value = &errorspb.Attribute_DurationValue{DurationValue: durationpb.New(value.Duration())}
+		case slog.KindGroup:
+			group := &errorspb.AttributeGroup{Attributes: attrsToProto(value.Group())}
+			pAttr.Value = &errorspb.Attribute_GroupValue{GroupValue: group}
+		default:
+			if values, ok := value.Any().([]string); ok {
+				pAttr.Value = &errorspb.Attribute_StringsValue{StringsValue: &errorspb.StringList{Values: values}}
+			} else {
+				pAttr.Value = &errorspb.Attribute_StringValue{StringValue: value.String()}
+			}
+		}
+
+		out = append(out, pAttr)
+	}
+
+	return out
+}
+
+func attrsFromProto(attrs []*errorspb.Attribute) []Attr {
+	out := make([]Attr, 0, len(attrs))
+
+	for _, pAttr := range attrs {
+		key := pAttr.GetKey()
+
+		switch value := pAttr.GetValue().(type) {
+		case *errorspb.Attribute_IntValue:
+			out = append(out, Int64Attr(key, value.IntValue))
+		case *errorspb.Attribute_UintValue:
+			out = append(out, Attr{Key: key, Value: slog.Uint64Value(value.UintValue)})
+		case *errorspb.Attribute_FloatValue:
+			out = append(out, FloatAttr(key, value.FloatValue))
+		case *errorspb.Attribute_BoolValue:
+			out = append(out, BoolAttr(key, value.BoolValue))
+		case *errorspb.Attribute_TimeValue:
+			out = append(out, TimeAttr(key, value.TimeValue.AsTime()))
+		case *errorspb.Attribute_DurationValue:
+			out = append(out, DurationAttr(key, value.DurationValue.AsDuration()))
+		case *errorspb.Attribute_StringsValue:
+			out = append(out, StringsAttr(key, value.StringsValue.GetValues()...))
+		case *errorspb.Attribute_GroupValue:
+			out = append(out, GroupAttr(key, attrsFromProto(value.GroupValue.GetAttributes())...))
+		default:
+			out = append(out, StringAttr(key, pAttr.GetStringValue()))
+		}
+	}
+
+	return out
+}
+
+// decodedError represents a native Go error decoded from the wire format.
+type decodedError struct {
+	msg  string
+	errs []error
+}
+
+// Error returns the decoded error message.
+func (d decodedError) Error() string {
+	return d.msg
+}
+
+// Unwrap unwraps the decoded causes of the error.
+func (d decodedError) Unwrap() []error {
+	return d.errs
+}
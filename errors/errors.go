@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	e "errors"
 	"fmt"
 )
@@ -12,8 +13,10 @@ import (
 // Retryable: Indicates if the given error may be fixed with a retry execution.
 //
 // It is designed to work well within a Go Error Tree.
+// It is encoded into a canonical JSON wire format. See [ToProto] and [FromJSON].
 type CustomError interface {
 	error
+	json.Marshaler
 
 	// WithKind return a copy of the CustomError with the given KindType filled.
 	WithKind(kind KindType) CustomError
@@ -28,20 +31,25 @@ type CustomError interface {
 	// WithAttribute returns a copy of the CustomError with the given Attribute filled.
 	WithAttribute(key, value string) CustomError
 
+	// WithAttrs returns a copy of the CustomError with the given typed Attributes filled.
+	WithAttrs(attrs ...Attr) CustomError
+
 	// Retryable returns a copy of the CustomError tagged as retryable.
 	Retryable() CustomError
 }
 
 type custom struct {
-	kind       KindType
-	code       CodeType
-	attributes AttributeSet
-	retryable  bool
-	errs       []error
+	kind      KindType
+	code      CodeType
+	attrs     []Attr
+	retryable bool
+	errs      []error
+	stack     stack
 }
 
 // New returns a new instance of CustomError with the given message.
 // It uses KindUnknown, CodeUnknown and 'false' by default for Kind, Code and Retryable attributes, respectively.
+// The call stack is captured when stack traces are enabled. See [SetStackTraceEnabled].
 func New(msg string, args ...any) CustomError {
 	err := e.New(msg)
 	if len(args) > 0 {
@@ -49,11 +57,12 @@ func New(msg string, args ...any) CustomError {
 	}
 
 	return custom{
-		kind:       KindUnknown,
-		code:       CodeUnknown,
-		attributes: AttributeSet{},
-		errs:       []error{err},
-		retryable:  false,
+		kind:      KindUnknown,
+		code:      CodeUnknown,
+		attrs:     []Attr{},
+		errs:      []error{err},
+		retryable: false,
+		stack:     callers(1),
 	}
 }
 
@@ -89,6 +98,8 @@ func (ce custom) Unwrap() []error {
 
 // WithKind return a copy of the CustomError with the given KindType filled.
 func (ce custom) WithKind(kind KindType) CustomError {
+	ce.ensureStack()
+
 	ce.kind = kind
 
 	return ce
@@ -96,6 +107,8 @@ func (ce custom) WithKind(kind KindType) CustomError {
 
 // WithCode return a copy of the CustomError with the given CodeType filled.
 func (ce custom) WithCode(code CodeType) CustomError {
+	ce.ensureStack()
+
 	ce.code = code
 
 	return ce
@@ -104,6 +117,8 @@ func (ce custom) WithCode(code CodeType) CustomError {
 // WithCause return a copy of the CustomError with the given Cause attached as the
 // last internal error of this CustomError.
 func (ce custom) WithCause(cause error) CustomError {
+	ce.ensureStack()
+
 	ce.errs = append(ce.errs, cause)
 
 	return ce
@@ -113,18 +128,39 @@ func (ce custom) WithCause(cause error) CustomError {
 //
 //nolint:gocritic // Ok.
 func (ce custom) WithAttribute(key, value string) CustomError {
-	ce.attributes[key] = value
+	ce.ensureStack()
+
+	ce.attrs = mergeAttrs(ce.attrs, []Attr{StringAttr(key, value)})
+
+	return ce
+}
+
+// WithAttrs returns a copy of the CustomError with the given typed Attributes filled.
+func (ce custom) WithAttrs(attrs ...Attr) CustomError {
+	ce.ensureStack()
+
+	ce.attrs = mergeAttrs(ce.attrs, attrs)
 
 	return ce
 }
 
 // Retryable returns a copy of the CustomError tagged as retryable.
 func (ce custom) Retryable() CustomError {
+	ce.ensureStack()
+
 	ce.retryable = true
 
 	return ce
 }
 
+// ensureStack captures the call stack of errors derived from the ones declared during package initialization,
+// so that sentinel errors carry the stack of the place they are actually returned from.
+func (ce *custom) ensureStack() {
+	if len(ce.stack) == 0 {
+		ce.stack = callers(2)
+	}
+}
+
 // Kind retrieves the first non unknown Kind in err's tree.
 // KindUnknown indicates that no Kind was set or no CustomError was found in the tree.
 //
@@ -182,22 +218,33 @@ func Severity(err error) SeverityType {
 }
 
 // Attributes retrieves all the attributes that defined in the given error.
+// Typed attributes are represented by their string values. See [Attrs] to keep their native types.
 func Attributes(err error) AttributeSet {
 	set := AttributeSet{}
 
+	for _, attr := range Attrs(err) {
+		set[attr.Key] = attr.Value.String()
+	}
+
+	return set
+}
+
+// Attrs retrieves all the typed attributes defined in the given error, keeping their native types.
+// Attributes of outer errors override the ones with the same key defined by their causes.
+func Attrs(err error) []Attr {
+	attrs := []Attr{}
+
 	//nolint:errorlint // we don't want to use [errors.As] here intentionally.
 	ce, ok := err.(custom)
 	if !ok {
-		return set
+		return attrs
 	}
 
 	for _, inner := range ce.Unwrap() {
-		set.Merge(Attributes(inner))
+		attrs = mergeAttrs(attrs, Attrs(inner))
 	}
 
-	set.Merge(ce.attributes)
-
-	return set
+	return mergeAttrs(attrs, ce.attrs)
 }
 
 // IsRetryable reports whether any error in err's tree is retryable.
@@ -307,17 +354,27 @@ func As(err error, target any) bool {
 
 // Wrap adds more contextual information into the given error.
 // The new information is handled as a new error which wraps the given error properly.
+// The call stack is captured when stack traces are enabled and the given error does not carry one yet.
 func Wrap(err error, msg string, args ...any) error {
 	toWrap := fmt.Errorf(msg, args...)
 
 	//nolint:errorlint // we don't want to use [errors.As] here intentionally.
 	if ce, ok := err.(custom); ok {
 		ce.errs = append([]error{toWrap}, ce.errs...)
+		if len(ce.stack) == 0 {
+			ce.stack = callers(1)
+		}
 
 		return ce
 	}
 
-	return e.Join(toWrap, err)
+	joined := e.Join(toWrap, err)
+
+	if s := callers(1); len(s) > 0 {
+		return stacked{err: joined, stack: s}
+	}
+
+	return joined
 }
 
 // Unwrap retrieves all the errors that forms the baseline for the given one.
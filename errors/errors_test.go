@@ -1,11 +1,14 @@
 package errors_test
 
 import (
+	"encoding/json"
 	e "errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lcnascimento/go-kit/errors"
 )
@@ -288,6 +291,59 @@ func TestAttributes(t *testing.T) {
 
 		assert.Equal(t, errors.AttributeSet{"key": "value", "key2": "value2"}, errors.Attributes(err))
 	})
+
+	t.Run("custom error with typed attributes", func(t *testing.T) {
+		err := errors.New("some message").WithAttrs(errors.IntAttr("status", 500), errors.BoolAttr("cached", true))
+		assert.Equal(t, errors.AttributeSet{"status": "500", "cached": "true"}, errors.Attributes(err))
+	})
+
+	t.Run("copies do not share attributes", func(t *testing.T) {
+		base := errors.New("some message")
+		err1 := base.WithAttribute("key", "value")
+		err2 := base.WithAttribute("key2", "value2")
+
+		assert.Equal(t, errors.AttributeSet{}, errors.Attributes(base))
+		assert.Equal(t, errors.AttributeSet{"key": "value"}, errors.Attributes(err1))
+		assert.Equal(t, errors.AttributeSet{"key2": "value2"}, errors.Attributes(err2))
+	})
+}
+
+func TestAttrs(t *testing.T) {
+	t.Run("go native error", func(t *testing.T) {
+		err := e.New("new error")
+		assert.Empty(t, errors.Attrs(err))
+	})
+
+	t.Run("custom error with typed attributes", func(t *testing.T) {
+		now := time.Now()
+		err := errors.New("some message").WithAttrs(
+			errors.IntAttr("int", 1),
+			errors.FloatAttr("float", 1.5),
+			errors.BoolAttr("bool", true),
+			errors.TimeAttr("time", now),
+			errors.DurationAttr("duration", time.Second),
+			errors.StringsAttr("strings", "a", "b"),
+			errors.GroupAttr("group", errors.StringAttr("nested", "value")),
+		)
+
+		attrs := errors.Attrs(err)
+		require.Len(t, attrs, 7)
+
+		assert.Equal(t, int64(1), attrs[0].Value.Int64())
+		assert.InDelta(t, 1.5, attrs[1].Value.Float64(), 0)
+		assert.True(t, attrs[2].Value.Bool())
+		assert.True(t, now.Equal(attrs[3].Value.Time()))
+		assert.Equal(t, time.Second, attrs[4].Value.Duration())
+		assert.Equal(t, []string{"a", "b"}, attrs[5].Value.Any())
+		assert.Equal(t, "value", attrs[6].Value.Group()[0].Value.String())
+	})
+
+	t.Run("custom error with nested typed attributes", func(t *testing.T) {
+		err1 := errors.New("nested").WithAttrs(errors.IntAttr("key", 1), errors.IntAttr("key2", 2))
+		err2 := errors.New("error").WithAttrs(errors.IntAttr("key", 3)).WithCause(err1)
+
+		assert.Equal(t, []errors.Attr{errors.IntAttr("key", 3), errors.IntAttr("key2", 2)}, errors.Attrs(err2))
+	})
 }
 
 func TestIsRetryable(t *testing.T) {
@@ -547,3 +603,167 @@ func TestWrap(t *testing.T) {
 		assert.Equal(t, expectedErrs, errors.Unwrap(wrapped))
 	})
 }
+
+func TestStackTrace(t *testing.T) {
+	t.Run("go native error", func(t *testing.T) {
+		err := e.New("native error")
+		assert.Empty(t, errors.StackTrace(err))
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		err := errors.New("some message")
+
+		frames := errors.StackTrace(err)
+		require.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestStackTrace")
+	})
+
+	t.Run("package level custom error", func(t *testing.T) {
+		assert.Empty(t, errors.StackTrace(errors.ErrRequestError))
+	})
+
+	t.Run("error derived from package level custom error", func(t *testing.T) {
+		err := errors.ErrRequestError.WithCause(e.New("cause"))
+
+		frames := errors.StackTrace(err)
+		require.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestStackTrace")
+	})
+
+	t.Run("custom error caused by other custom error", func(t *testing.T) {
+		cause := errors.New("cause")
+		err := e.Join(e.New("native"), cause)
+
+		assert.Equal(t, errors.StackTrace(cause), errors.StackTrace(err))
+	})
+
+	t.Run("wrap go native error", func(t *testing.T) {
+		err := errors.Wrap(e.New("native error"), "wrapped error")
+
+		frames := errors.StackTrace(err)
+		require.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestStackTrace")
+	})
+
+	t.Run("disabled capturing", func(t *testing.T) {
+		errors.SetStackTraceEnabled(false)
+		defer errors.SetStackTraceEnabled(true)
+
+		assert.False(t, errors.StackTraceEnabled())
+		assert.Empty(t, errors.StackTrace(errors.New("some message")))
+		assert.Empty(t, errors.StackTrace(errors.Wrap(e.New("native error"), "wrapped error")))
+	})
+}
+
+func TestJSON(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	native := fmt.Errorf("wrapped native: %w", e.New("native cause"))
+	inner := errors.New("inner error").
+		WithKind(errors.KindNotFound).
+		WithCode("ERR_INNER").
+		WithAttrs(errors.StringsAttr("ids", "1", "2"), errors.GroupAttr("group", errors.BoolAttr("ok", true)))
+
+	err := errors.New("outer error").
+		WithKind(errors.KindInternal).
+		WithCode("ERR_OUTER").
+		WithAttribute("key", "value").
+		WithAttrs(
+			errors.IntAttr("int", 1),
+			errors.FloatAttr("float", 1.5),
+			errors.TimeAttr("time", now),
+			errors.DurationAttr("duration", time.Second),
+		).
+		WithCause(native).
+		WithCause(inner).
+		Retryable()
+
+	data, jErr := json.Marshal(err)
+	require.NoError(t, jErr)
+
+	decoded, jErr := errors.FromJSON(data)
+	require.NoError(t, jErr)
+
+	t.Run("should keep the error data", func(t *testing.T) {
+		assert.Equal(t, err.Error(), decoded.Error())
+		assert.Equal(t, errors.Kind(err), errors.Kind(decoded))
+		assert.Equal(t, errors.Code(err), errors.Code(decoded))
+		assert.Equal(t, errors.IsRetryable(err), errors.IsRetryable(decoded))
+		assert.Equal(t, errors.Reasons(err), errors.Reasons(decoded))
+		assert.Equal(t, errors.SafeReasons(err), errors.SafeReasons(decoded))
+		assert.True(t, errors.Is(decoded, err))
+	})
+
+	t.Run("should keep typed attributes", func(t *testing.T) {
+		attrs := errors.Attrs(decoded)
+		require.Len(t, attrs, 7)
+
+		assert.Equal(t, errors.Attributes(err), errors.Attributes(decoded))
+		assert.Equal(t, errors.Attrs(err)[3], attrs[3])
+		assert.True(t, now.Equal(attrs[5].Value.Time()))
+		assert.Equal(t, time.Second, attrs[6].Value.Duration())
+	})
+
+	t.Run("should keep the causes tree", func(t *testing.T) {
+		causes := errors.Unwrap(decoded)
+		require.Len(t, causes, 3)
+
+		assert.Equal(t, "wrapped native: native cause", causes[1].Error())
+		require.Len(t, errors.Unwrap(causes[1]), 1)
+		assert.Equal(t, "native cause", errors.Unwrap(causes[1])[0].Error())
+		assert.Equal(t, errors.KindNotFound, errors.Kind(causes[2]))
+		assert.Equal(t, errors.CodeType("ERR_INNER"), errors.Code(causes[2]))
+	})
+
+	t.Run("should produce the same wire format", func(t *testing.T) {
+		again, jErr := json.Marshal(decoded)
+		require.NoError(t, jErr)
+
+		assert.JSONEq(t, string(data), string(again))
+	})
+
+	t.Run("should be decoded from protobuf", func(t *testing.T) {
+		assert.Equal(t, string(data), string(mustMarshal(t, errors.FromProto(errors.ToProto(err)))))
+	})
+
+	t.Run("should leave native errors out of safe protobuf", func(t *testing.T) {
+		wrapped := errors.New("outer error").WithKind(errors.KindInternal).WithCause(fmt.Errorf("native: %w", inner))
+
+		safe := errors.FromProto(errors.ToSafeProto(wrapped))
+
+		assert.Contains(t, errors.Reasons(wrapped), "native: inner error")
+		assert.Equal(t, []string{"inner error"}, errors.Reasons(safe))
+		assert.Equal(t, errors.KindNotFound, errors.Kind(errors.Unwrap(safe)[1]))
+	})
+
+	t.Run("should be decoded into struct fields", func(t *testing.T) {
+		type payload struct {
+			Error errors.Envelope `json:"error"`
+		}
+
+		var got payload
+		require.NoError(t, json.Unmarshal(mustMarshal(t, payload{Error: errors.Envelope{CustomError: err}}), &got))
+
+		require.NotNil(t, got.Error.CustomError)
+		assert.JSONEq(t, string(data), string(mustMarshal(t, got.Error)))
+
+		got = payload{}
+		require.NoError(t, json.Unmarshal([]byte(`{"error":null}`), &got))
+		assert.Nil(t, got.Error.CustomError)
+		assert.JSONEq(t, `{"error":null}`, string(mustMarshal(t, got)))
+	})
+
+	t.Run("should fail on invalid payload", func(t *testing.T) {
+		_, jErr := errors.FromJSON([]byte("invalid"))
+		assert.Error(t, jErr)
+	})
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return data
+}
@@ -0,0 +1,523 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: errorspb/errors.proto
+
+package errorspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is the canonical wire format of an error tree.
+// CustomErrors always have a non empty kind. Errors with an empty kind are native Go errors.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message is the error message.
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// kind gives semantics for the error. It is empty for native Go errors.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// code defines what the error actually is, by an unique alias.
+	Code string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	// retryable indicates if the error may be fixed with a retry execution.
+	Retryable bool `protobuf:"varint,4,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// attributes holds the typed attributes of the error.
+	Attributes []*Attribute `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	// causes holds the errors that led to the creation of this one, in order.
+	Causes        []*Error `protobuf:"bytes,6,rep,name=causes,proto3" json:"causes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_errorspb_errors_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_errorspb_errors_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_errorspb_errors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *Error) GetAttributes() []*Attribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Error) GetCauses() []*Error {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+// Attribute is a typed key-value pair attached to an error.
+type Attribute struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the attribute name.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// value is the attribute typed value.
+	//
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Attribute_StringValue
+	//	*Attribute_IntValue
+	//	*Attribute_UintValue
+	//	*Attribute_FloatValue
+	//	*Attribute_BoolValue
+	//	*Attribute_TimeValue
+	//	*Attribute_DurationValue
+	//	*Attribute_StringsValue
+	//	*Attribute_GroupValue
+	Value         isAttribute_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attribute) Reset() {
+	*x = Attribute{}
+	mi := &file_errorspb_errors_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attribute) ProtoMessage() {}
+
+func (x *Attribute) ProtoReflect() protoreflect.Message {
+	mi := &file_errorspb_errors_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attribute.ProtoReflect.Descriptor instead.
+func (*Attribute) Descriptor() ([]byte, []int) {
+	return file_errorspb_errors_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attribute) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Attribute) GetValue() isAttribute_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Attribute) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *Attribute) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *Attribute) GetUintValue() uint64 {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_UintValue); ok {
+			return x.UintValue
+		}
+	}
+	return 0
+}
+
+func (x *Attribute) GetFloatValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_FloatValue); ok {
+			return x.FloatValue
+		}
+	}
+	return 0
+}
+
+func (x *Attribute) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *Attribute) GetTimeValue() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_TimeValue); ok {
+			return x.TimeValue
+		}
+	}
+	return nil
+}
+
+func (x *Attribute) GetDurationValue() *durationpb.Duration {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_DurationValue); ok {
+			return x.DurationValue
+		}
+	}
+	return nil
+}
+
+func (x *Attribute) GetStringsValue() *StringList {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_StringsValue); ok {
+			return x.StringsValue
+		}
+	}
+	return nil
+}
+
+func (x *Attribute) GetGroupValue() *AttributeGroup {
+	if x != nil {
+		if x, ok := x.Value.(*Attribute_GroupValue); ok {
+			return x.GroupValue
+		}
+	}
+	return nil
+}
+
+type isAttribute_Value interface {
+	isAttribute_Value()
+}
+
+type Attribute_StringValue struct {
+	StringValue string `protobuf:"bytes,2,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Attribute_IntValue struct {
+	IntValue int64 `protobuf:"varint,3,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Attribute_UintValue struct {
+	UintValue uint64 `protobuf:"varint,4,opt,name=uint_value,json=uintValue,proto3,oneof"`
+}
+
+type Attribute_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,5,opt,name=float_value,json=floatValue,proto3,oneof"`
+}
+
+type Attribute_BoolValue struct {
+	BoolValue bool `protobuf:"varint,6,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Attribute_TimeValue struct {
+	TimeValue *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time_value,json=timeValue,proto3,oneof"`
+}
+
+type Attribute_DurationValue struct {
+	DurationValue *durationpb.Duration `protobuf:"bytes,8,opt,name=duration_value,json=durationValue,proto3,oneof"`
+}
+
+type Attribute_StringsValue struct {
+	StringsValue *StringList `protobuf:"bytes,9,opt,name=strings_value,json=stringsValue,proto3,oneof"`
+}
+
+type Attribute_GroupValue struct {
+	GroupValue *AttributeGroup `protobuf:"bytes,10,opt,name=group_value,json=groupValue,proto3,oneof"`
+}
+
+func (*Attribute_StringValue) isAttribute_Value() {}
+
+func (*Attribute_IntValue) isAttribute_Value() {}
+
+func (*Attribute_UintValue) isAttribute_Value() {}
+
+func (*Attribute_FloatValue) isAttribute_Value() {}
+
+func (*Attribute_BoolValue) isAttribute_Value() {}
+
+func (*Attribute_TimeValue) isAttribute_Value() {}
+
+func (*Attribute_DurationValue) isAttribute_Value() {}
+
+func (*Attribute_StringsValue) isAttribute_Value() {}
+
+func (*Attribute_GroupValue) isAttribute_Value() {}
+
+// StringList is a list of strings.
+type StringList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringList) Reset() {
+	*x = StringList{}
+	mi := &file_errorspb_errors_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringList) ProtoMessage() {}
+
+func (x *StringList) ProtoReflect() protoreflect.Message {
+	mi := &file_errorspb_errors_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringList.ProtoReflect.Descriptor instead.
+func (*StringList) Descriptor() ([]byte, []int) {
+	return file_errorspb_errors_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StringList) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// AttributeGroup is a nested group of attributes.
+type AttributeGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attributes    []*Attribute           `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttributeGroup) Reset() {
+	*x = AttributeGroup{}
+	mi := &file_errorspb_errors_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttributeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeGroup) ProtoMessage() {}
+
+func (x *AttributeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_errorspb_errors_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeGroup.ProtoReflect.Descriptor instead.
+func (*AttributeGroup) Descriptor() ([]byte, []int) {
+	return file_errorspb_errors_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AttributeGroup) GetAttributes() []*Attribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+var File_errorspb_errors_proto protoreflect.FileDescriptor
+
+const file_errorspb_errors_proto_rawDesc = "" +
+	"\n" +
+	"\x15errorspb/errors.proto\x12\x0fgokit.errors.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd3\x01\n" +
+	"\x05Error\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\x12:\n" +
+	"\n" +
+	"attributes\x18\x05 \x03(\v2\x1a.gokit.errors.v1.AttributeR\n" +
+	"attributes\x12.\n" +
+	"\x06causes\x18\x06 \x03(\v2\x16.gokit.errors.v1.ErrorR\x06causes\"\xd8\x03\n" +
+	"\tAttribute\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12#\n" +
+	"\fstring_value\x18\x02 \x01(\tH\x00R\vstringValue\x12\x1d\n" +
+	"\tint_value\x18\x03 \x01(\x03H\x00R\bintValue\x12\x1f\n" +
+	"\n" +
+	"uint_value\x18\x04 \x01(\x04H\x00R\tuintValue\x12!\n" +
+	"\vfloat_value\x18\x05 \x01(\x01H\x00R\n" +
+	"floatValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x06 \x01(\bH\x00R\tboolValue\x12;\n" +
+	"\n" +
+	"time_value\x18\a \x01(\v2\x1a.google.protobuf.TimestampH\x00R\ttimeValue\x12B\n" +
+	"\x0eduration_value\x18\b \x01(\v2\x19.google.protobuf.DurationH\x00R\rdurationValue\x12B\n" +
+	"\rstrings_value\x18\t \x01(\v2\x1b.gokit.errors.v1.StringListH\x00R\fstringsValue\x12B\n" +
+	"\vgroup_value\x18\n" +
+	" \x01(\v2\x1f.gokit.errors.v1.AttributeGroupH\x00R\n" +
+	"groupValueB\a\n" +
+	"\x05value\"$\n" +
+	"\n" +
+	"StringList\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"L\n" +
+	"\x0eAttributeGroup\x12:\n" +
+	"\n" +
+	"attributes\x18\x01 \x03(\v2\x1a.gokit.errors.v1.AttributeR\n" +
+	"attributesB0Z.github.com/lcnascimento/go-kit/errors/errorspbb\x06proto3"
+
+var (
+	file_errorspb_errors_proto_rawDescOnce sync.Once
+	file_errorspb_errors_proto_rawDescData []byte
+)
+
+func file_errorspb_errors_proto_rawDescGZIP() []byte {
+	file_errorspb_errors_proto_rawDescOnce.Do(func() {
+		file_errorspb_errors_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_errorspb_errors_proto_rawDesc), len(file_errorspb_errors_proto_rawDesc)))
+	})
+	return file_errorspb_errors_proto_rawDescData
+}
+
+var file_errorspb_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_errorspb_errors_proto_goTypes = []any{
+	(*Error)(nil),                 // 0: gokit.errors.v1.Error
+	(*Attribute)(nil),             // 1: gokit.errors.v1.Attribute
+	(*StringList)(nil),            // 2: gokit.errors.v1.StringList
+	(*AttributeGroup)(nil),        // 3: gokit.errors.v1.AttributeGroup
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 5: google.protobuf.Duration
+}
+var file_errorspb_errors_proto_depIdxs = []int32{
+	1, // 0: gokit.errors.v1.Error.attributes:type_name -> gokit.errors.v1.Attribute
+	0, // 1: gokit.errors.v1.Error.causes:type_name -> gokit.errors.v1.Error
+	4, // 2: gokit.errors.v1.Attribute.time_value:type_name -> google.protobuf.Timestamp
+	5, // 3: gokit.errors.v1.Attribute.duration_value:type_name -> google.protobuf.Duration
+	2, // 4: gokit.errors.v1.Attribute.strings_value:type_name -> gokit.errors.v1.StringList
+	3, // 5: gokit.errors.v1.Attribute.group_value:type_name -> gokit.errors.v1.AttributeGroup
+	1, // 6: gokit.errors.v1.AttributeGroup.attributes:type_name -> gokit.errors.v1.Attribute
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_errorspb_errors_proto_init() }
+func file_errorspb_errors_proto_init() {
+	if File_errorspb_errors_proto != nil {
+		return
+	}
+	file_errorspb_errors_proto_msgTypes[1].OneofWrappers = []any{
+		(*Attribute_StringValue)(nil),
+		(*Attribute_IntValue)(nil),
+		(*Attribute_UintValue)(nil),
+		(*Attribute_FloatValue)(nil),
+		(*Attribute_BoolValue)(nil),
+		(*Attribute_TimeValue)(nil),
+		(*Attribute_DurationValue)(nil),
+		(*Attribute_StringsValue)(nil),
+		(*Attribute_GroupValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_errorspb_errors_proto_rawDesc), len(file_errorspb_errors_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_errorspb_errors_proto_goTypes,
+		DependencyIndexes: file_errorspb_errors_proto_depIdxs,
+		MessageInfos:      file_errorspb_errors_proto_msgTypes,
+	}.Build()
+	File_errorspb_errors_proto = out.File
+	file_errorspb_errors_proto_goTypes = nil
+	file_errorspb_errors_proto_depIdxs = nil
+}
@@ -1,6 +1,7 @@
 package sample
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/lcnascimento/go-kit/errors"
@@ -14,6 +15,8 @@ func main() {
 	errors.New("error with code").WithCode("MY_CUSTOM_CODE")
 	errors.New("error with cause").WithCause(fmt.Errorf("root error"))
 	errors.New("error retryable").Retryable()
+	errors.New("error with attributes").WithAttribute("key", "value")
+	errors.New("error with typed attributes").WithAttrs(errors.IntAttr("status", 500), errors.BoolAttr("cached", false))
 	errors.New("error fully filled").
 		WithKind(errors.KindInternal).
 		WithCode("ERR_GET_ACCOUNT").
@@ -25,6 +28,15 @@ func main() {
 	errors.Kind(errors.ErrResourceNotFound)        // KindNotFound
 	errors.IsRetryable(errors.ErrResourceNotFound) // false
 
+	// stack traces, controlled by the ERRORS_STACK_TRACE_ENABLED env var
+	errors.StackTrace(errors.New("error with stack")) // frames of the place where the error was created
+	errors.SetStackTraceEnabled(false)                // disables capturing on hot paths
+
+	// wire format, for sending errors across process boundaries
+	data, _ := json.Marshal(errors.New("error to be sent").WithCode("ERR_SENT"))
+	errors.FromJSON(data)                                      // same error, with kind, code, attributes and causes
+	errors.FromProto(errors.ToProto(errors.ErrNotImplemented)) // protobuf equivalent
+
 	// go-like utility features
 	wrapped := errors.Wrap(errors.ErrResourceNotFound, "could not find the requested account")
 	errors.Unwrap(wrapped)                                          // ["could not find the requested account", "resource not found"]
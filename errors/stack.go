@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of program counters captured for a single error.
+const maxStackDepth = 32
+
+var stackTraceEnabled atomic.Bool
+
+// init enables stack traces unless the ERRORS_STACK_TRACE_ENABLED env var holds a false value.
+func init() {
+	enabled, err := strconv.ParseBool(os.Getenv("ERRORS_STACK_TRACE_ENABLED"))
+	stackTraceEnabled.Store(err != nil || enabled)
+}
+
+// SetStackTraceEnabled turns stack trace capturing on or off for every error created from now on.
+// It is safe to be called concurrently. The initial value is driven by the ERRORS_STACK_TRACE_ENABLED env var.
+func SetStackTraceEnabled(enabled bool) {
+	stackTraceEnabled.Store(enabled)
+}
+
+// StackTraceEnabled reports whether stack trace capturing is currently enabled.
+func StackTraceEnabled() bool {
+	return stackTraceEnabled.Load()
+}
+
+// StackTrace retrieves the first stack trace found in err's tree.
+// An empty slice indicates that capturing was disabled or no error in the tree holds a stack trace.
+//
+// The tree consists of err itself, followed by the errors obtained by repeatedly calling its Unwrap() error
+// or Unwrap() []error method. When err wraps multiple errors, StackTrace examines err followed by a depth-first
+// traversal of its children.
+func StackTrace(err error) []runtime.Frame {
+	//nolint:errorlint // we don't want to use [errors.As] here intentionally.
+	switch ce := err.(type) {
+	case custom:
+		if len(ce.stack) > 0 {
+			return ce.stack.frames()
+		}
+	case stacked:
+		return ce.stack.frames()
+	}
+
+	for _, inner := range Unwrap(err) {
+		if frames := StackTrace(inner); len(frames) > 0 {
+			return frames
+		}
+	}
+
+	return []runtime.Frame{}
+}
+
+// stack is a compact representation of a call stack, made of raw program counters.
+// Program counters are only resolved into frames when the stack trace is requested.
+type stack []uintptr
+
+// callers captures the current call stack, skipping the given number of errors package frames
+// on top of callers itself.
+// Errors declared during package initialization do not get a stack, since it would point
+// to the var declaration instead of the place the error was actually returned from.
+func callers(skip int) stack {
+	if !stackTraceEnabled.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+
+	// skips runtime.Callers and callers itself.
+	n := runtime.Callers(2+skip, pcs)
+	if n == 0 {
+		return nil
+	}
+
+	first, _ := runtime.CallersFrames(pcs[:1]).Next()
+	if isPackageInit(first.Function) {
+		return nil
+	}
+
+	return pcs[:n]
+}
+
+func (s stack) frames() []runtime.Frame {
+	frames := make([]runtime.Frame, 0, len(s))
+
+	iter := runtime.CallersFrames(s)
+	for {
+		frame, more := iter.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			frames = append(frames, frame)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+func isPackageInit(function string) bool {
+	return strings.HasSuffix(function, ".init") || strings.Contains(function, ".init.")
+}
+
+// stacked attaches a stack trace to errors that are not CustomErrors, preserving their tree.
+type stacked struct {
+	err   error
+	stack stack
+}
+
+// Error returns the wrapped error message.
+func (s stacked) Error() string {
+	return s.err.Error()
+}
+
+// Unwrap unwraps the errors that are baselines for the wrapped error.
+func (s stacked) Unwrap() []error {
+	return Unwrap(s.err)
+}
@@ -1,5 +1,10 @@
 package errors
 
+import (
+	"log/slog"
+	"time"
+)
+
 var (
 	// ErrNotImplemented indicates that a given feature is not implemented yet.
 	ErrNotImplemented error = New("feature not implemented yet").
@@ -90,6 +95,26 @@ const (
 	KindWarn               KindType = "WARN"
 )
 
+// Kinds returns every kind of error.
+func Kinds() []KindType {
+	return []KindType{
+		KindUnknown,
+		KindConflict,
+		KindInternal,
+		KindInvalidInput,
+		KindNotFound,
+		KindUnauthenticated,
+		KindUnauthorized,
+		KindUnprocessable,
+		KindResourceExhausted,
+		KindServiceUnavailable,
+		KindCritical,
+		KindFatal,
+		KindCanceled,
+		KindWarn,
+	}
+}
+
 type SeverityType int
 
 const (
@@ -128,3 +153,78 @@ func (s AttributeSet) Merge(other AttributeSet) {
 		s[key] = value
 	}
 }
+
+// Attr is a typed key-value pair that can be used to add additional information to an error.
+// It keeps the value native type, so that it can be properly emitted by logs and spans.
+type Attr = slog.Attr
+
+// StringAttr returns an Attr for a string value.
+func StringAttr(key, value string) Attr {
+	return slog.String(key, value)
+}
+
+// IntAttr returns an Attr for an int value.
+func IntAttr(key string, value int) Attr {
+	return slog.Int(key, value)
+}
+
+// Int64Attr returns an Attr for an int64 value.
+func Int64Attr(key string, value int64) Attr {
+	return slog.Int64(key, value)
+}
+
+// FloatAttr returns an Attr for a float64 value.
+func FloatAttr(key string, value float64) Attr {
+	return slog.Float64(key, value)
+}
+
+// BoolAttr returns an Attr for a bool value.
+func BoolAttr(key string, value bool) Attr {
+	return slog.Bool(key, value)
+}
+
+// TimeAttr returns an Attr for a [time.Time] value.
+func TimeAttr(key string, value time.Time) Attr {
+	return slog.Time(key, value)
+}
+
+// DurationAttr returns an Attr for a [time.Duration] value.
+func DurationAttr(key string, value time.Duration) Attr {
+	return slog.Duration(key, value)
+}
+
+// StringsAttr returns an Attr for a slice of strings.
+func StringsAttr(key string, values ...string) Attr {
+	return slog.Any(key, values)
+}
+
+// GroupAttr returns an Attr for a nested group of attributes.
+func GroupAttr(key string, attrs ...Attr) Attr {
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// mergeAttrs merges src into dst, overriding the attributes that share the same key.
+// It never modifies dst backing array, so CustomError copies do not share attributes.
+func mergeAttrs(dst, src []Attr) []Attr {
+	out := make([]Attr, len(dst), len(dst)+len(src))
+	copy(out, dst)
+
+	for _, attr := range src {
+		replaced := false
+
+		for i := range out {
+			if out[i].Key == attr.Key {
+				out[i] = attr
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			out = append(out, attr)
+		}
+	}
+
+	return out
+}
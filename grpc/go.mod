@@ -9,6 +9,7 @@ replace github.com/lcnascimento/go-kit/o11y => ../o11y
 replace github.com/lcnascimento/go-kit/env => ../env
 
 require (
+	github.com/lcnascimento/go-kit/env v0.0.0-00010101000000-000000000000
 	github.com/lcnascimento/go-kit/errors v0.0.0-00010101000000-000000000000
 	github.com/lcnascimento/go-kit/o11y v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.11.1
@@ -27,7 +28,6 @@ require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.29.0 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/lcnascimento/go-kit/env v0.0.0-00010101000000-000000000000 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/contrib/bridges/otelslog v0.19.0 // indirect
@@ -0,0 +1,137 @@
+package grpcclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/lcnascimento/go-kit/env"
+
+	"github.com/lcnascimento/go-kit/grpc/grpcclient/interceptor"
+)
+
+const (
+	kb                      = 1024
+	mb                      = kb * kb
+	defaultFileMaxSize      = 20 * mb            // 20mb
+	defaultGrpcMaxSize      = defaultFileMaxSize // should be the same size of file (upload size)
+	defaultMaxGrpcMsgSize   = defaultGrpcMaxSize + mb
+	defaultKeepAliveTimeout = time.Second * 10
+	defaultPingInterval     = time.Second * 30
+)
+
+type config struct {
+	dialOpts  []grpc.DialOption
+	otelOpts  []otelgrpc.Option
+	tlsConfig *tls.Config
+	insecure  bool
+}
+
+// NewClient creates a new gRPC client connection to the given target with the given options.
+//
+// Transport security is driven by the following env vars, unless overridden by [WithInsecure] or [WithTLSConfig]:
+//   - GRPC_CLIENT_INSECURE: disables transport security. Defaults to false.
+//   - GRPC_CLIENT_TLS_CA_FILE: PEM encoded CA certificates used to verify the server. Defaults to the system pool.
+//   - GRPC_CLIENT_TLS_CERT_FILE and GRPC_CLIENT_TLS_KEY_FILE: client certificate for mutual TLS.
+//   - GRPC_CLIENT_TLS_SERVER_NAME: overrides the server name used to verify the server certificate.
+func NewClient(target string, opts ...Option) (*grpc.ClientConn, error) {
+	cfg := &config{
+		insecure: env.Get("GRPC_CLIENT_INSECURE", env.WithDefaultValue(false)),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	creds, err := cfg.transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	kaParams := keepalive.ClientParameters{
+		Time:                defaultPingInterval,     // how often to ping
+		Timeout:             defaultKeepAliveTimeout, // how long will we wait for a reply
+		PermitWithoutStream: true,                    // ping even without ongoing streams
+	}
+
+	//nolint:prealloc // OK
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(kaParams),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(defaultMaxGrpcMsgSize),
+			grpc.MaxCallSendMsgSize(defaultMaxGrpcMsgSize),
+		),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(cfg.otelOpts...)),
+		grpc.WithChainUnaryInterceptor(
+			interceptor.UnaryLogging(),
+			interceptor.UnaryErrorHandler(),
+		),
+		grpc.WithChainStreamInterceptor(
+			interceptor.StreamLogging(),
+			interceptor.StreamErrorHandler(),
+		),
+	}
+
+	dialOpts = append(dialOpts, cfg.dialOpts...)
+	return grpc.NewClient(target, dialOpts...)
+}
+
+func (c *config) transportCredentials() (credentials.TransportCredentials, error) {
+	if c.insecure {
+		return insecure.NewCredentials(), nil
+	}
+
+	if c.tlsConfig != nil {
+		return credentials.NewTLS(c.tlsConfig), nil
+	}
+
+	tlsConfig, err := tlsConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func tlsConfigFromEnv() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: env.Get[string]("GRPC_CLIENT_TLS_SERVER_NAME"),
+	}
+
+	if caFile := env.Get[string]("GRPC_CLIENT_TLS_CA_FILE"); caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, ErrLoadTLSCredentials.WithAttribute("file", caFile)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	certFile := env.Get[string]("GRPC_CLIENT_TLS_CERT_FILE")
+	keyFile := env.Get[string]("GRPC_CLIENT_TLS_KEY_FILE")
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
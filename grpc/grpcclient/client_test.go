@@ -0,0 +1,47 @@
+package grpcclient_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/grpc/grpcclient"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("should create an insecure client", func(t *testing.T) {
+		conn, err := grpcclient.NewClient("localhost:50051", grpcclient.WithInsecure())
+		require.NoError(t, err)
+
+		assert.Equal(t, "localhost:50051", conn.Target())
+		assert.NoError(t, conn.Close())
+	})
+
+	t.Run("should create a TLS client from env", func(t *testing.T) {
+		t.Setenv("GRPC_CLIENT_TLS_SERVER_NAME", "localhost")
+
+		conn, err := grpcclient.NewClient("localhost:50051")
+		require.NoError(t, err)
+
+		assert.NoError(t, conn.Close())
+	})
+
+	t.Run("should fail when the CA file could not be loaded", func(t *testing.T) {
+		t.Setenv("GRPC_CLIENT_TLS_CA_FILE", "./unknown.pem")
+
+		_, err := grpcclient.NewClient("localhost:50051")
+		assert.True(t, errors.Is(err, grpcclient.ErrLoadTLSCredentials))
+	})
+
+	t.Run("should ignore env TLS config when insecure", func(t *testing.T) {
+		t.Setenv("GRPC_CLIENT_INSECURE", "true")
+		t.Setenv("GRPC_CLIENT_TLS_CA_FILE", "./unknown.pem")
+
+		conn, err := grpcclient.NewClient("localhost:50051")
+		require.NoError(t, err)
+
+		assert.NoError(t, conn.Close())
+	})
+}
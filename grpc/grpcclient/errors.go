@@ -0,0 +1,7 @@
+package grpcclient
+
+import "github.com/lcnascimento/go-kit/errors"
+
+var ErrLoadTLSCredentials = errors.New("could not load TLS credentials").
+	WithCode("ERR_LOAD_TLS_CREDENTIALS").
+	WithKind(errors.KindInvalidInput)
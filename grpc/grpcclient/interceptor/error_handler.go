@@ -0,0 +1,189 @@
+package interceptor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/errors/errorspb"
+)
+
+// UnaryErrorHandler returns a new unary client interceptor that decodes gRPC status errors into CustomErrors.
+func UnaryErrorHandler() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return DecodeError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamErrorHandler returns a new stream client interceptor that decodes gRPC status errors into CustomErrors.
+func StreamErrorHandler() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, DecodeError(err)
+		}
+
+		return &errorDecoderStream{ClientStream: cs}, nil
+	}
+}
+
+// DecodeError rebuilds a CustomError from a gRPC status error, as encoded by the grpcserver error handler.
+// The error is decoded from its canonical protobuf wire format carried by the status details, keeping its kind,
+// attributes and causes tree. See [errors.FromProto]. Status errors of servers that do not send the wire format
+// have their Kind mapped back from the status code, while Code, Retryable and reasons are parsed from
+// the ErrorInfo details. The original status error is kept as a cause, so status.Code still works on it.
+// Errors that do not carry a gRPC status, or that are already CustomErrors, are returned untouched.
+func DecodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	//nolint:errorlint // we don't want to use [errors.As] here intentionally.
+	if _, decoded := err.(errors.CustomError); decoded {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	if encoded := wireError(st); encoded != nil {
+		return errors.FromProto(encoded).WithCause(err)
+	}
+
+	ce := errors.New("%s", st.Message()).WithKind(grpcStatusCodeToKind(st.Code()))
+
+	info := errorInfo(st)
+	if info == nil {
+		if st.Code() == codes.Unavailable {
+			ce = ce.Retryable()
+		}
+
+		return ce.WithCause(err)
+	}
+
+	if code := info.GetMetadata()["code"]; code != "" {
+		ce = ce.WithCode(errors.CodeType(code))
+	}
+
+	if retryable, _ := strconv.ParseBool(info.GetMetadata()["retryable"]); retryable {
+		ce = ce.Retryable()
+	}
+
+	for _, reason := range decodeReasons(info.GetMetadata()["reasons"]) {
+		ce = ce.WithCause(errors.New("%s", reason))
+	}
+
+	return ce.WithCause(err)
+}
+
+// wireError returns the error encoded in its canonical protobuf wire format by the status details, if any.
+func wireError(st *status.Status) *errorspb.Error {
+	for _, detail := range st.Details() {
+		if encoded, ok := detail.(*errorspb.Error); ok {
+			return encoded
+		}
+	}
+
+	return nil
+}
+
+func errorInfo(st *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+
+	return nil
+}
+
+// decodeReasons parses reasons encoded by servers that do not send the wire format as a sequence of "<length>#<reason>" entries.
+// Parsing stops at the first malformed entry.
+func decodeReasons(encoded string) []string {
+	reasons := []string{}
+
+	for encoded != "" {
+		size, rest, found := strings.Cut(encoded, "#")
+		if !found {
+			break
+		}
+
+		length, err := strconv.Atoi(size)
+		if err != nil || length < 0 || length > len(rest) {
+			break
+		}
+
+		reasons = append(reasons, rest[:length])
+		encoded = rest[length:]
+	}
+
+	return reasons
+}
+
+func grpcStatusCodeToKind(code codes.Code) errors.KindType {
+	//nolint:exhaustive // codes without a matching kind fall back to KindUnknown.
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return errors.KindInvalidInput
+	case codes.Unauthenticated:
+		return errors.KindUnauthenticated
+	case codes.PermissionDenied:
+		return errors.KindUnauthorized
+	case codes.NotFound:
+		return errors.KindNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.Aborted:
+		return errors.KindConflict
+	case codes.Internal, codes.DataLoss:
+		return errors.KindInternal
+	case codes.ResourceExhausted:
+		return errors.KindResourceExhausted
+	case codes.Unavailable:
+		return errors.KindServiceUnavailable
+	case codes.Canceled, codes.DeadlineExceeded:
+		return errors.KindCanceled
+	default:
+		return errors.KindUnknown
+	}
+}
+
+// errorDecoderStream decodes the errors returned by the wrapped client stream.
+type errorDecoderStream struct {
+	grpc.ClientStream
+}
+
+// SendMsg sends a message through the stream, decoding the returned error.
+func (s *errorDecoderStream) SendMsg(m any) error {
+	return DecodeError(s.ClientStream.SendMsg(m))
+}
+
+// RecvMsg receives a message from the stream, decoding the returned error.
+func (s *errorDecoderStream) RecvMsg(m any) error {
+	return DecodeError(s.ClientStream.RecvMsg(m))
+}
+
+// CloseSend closes the send direction of the stream, decoding the returned error.
+func (s *errorDecoderStream) CloseSend() error {
+	return DecodeError(s.ClientStream.CloseSend())
+}
@@ -0,0 +1,158 @@
+package interceptor_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/grpc/grpcclient/interceptor"
+	server "github.com/lcnascimento/go-kit/grpc/grpcserver/interceptor"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tt := []struct {
+		desc      string
+		err       error
+		kind      errors.KindType
+		code      errors.CodeType
+		retryable bool
+		reasons   []string
+		status    codes.Code
+	}{
+		{
+			desc:    "error with code",
+			err:     errors.New("test error").WithCode("test_code"),
+			kind:    errors.KindUnknown,
+			code:    "test_code",
+			reasons: []string{},
+			status:  codes.Unknown,
+		},
+		{
+			desc:      "error retryable",
+			err:       errors.New("test error").Retryable(),
+			kind:      errors.KindUnknown,
+			code:      errors.CodeUnknown,
+			retryable: true,
+			reasons:   []string{},
+			status:    codes.Unknown,
+		},
+		{
+			desc:    "error with kind",
+			err:     errors.New("test error").WithKind(errors.KindNotFound),
+			kind:    errors.KindNotFound,
+			code:    errors.CodeUnknown,
+			reasons: []string{},
+			status:  codes.NotFound,
+		},
+		{
+			desc:      "error with kind service unavailable",
+			err:       errors.New("test error").WithKind(errors.KindServiceUnavailable).Retryable(),
+			kind:      errors.KindServiceUnavailable,
+			code:      errors.CodeUnknown,
+			retryable: true,
+			reasons:   []string{},
+			status:    codes.Unavailable,
+		},
+		{
+			desc:    "error with reasons",
+			err:     errors.New("test error").WithCause(errors.New("reason#1")).WithCause(errors.New("reason 2")),
+			kind:    errors.KindUnknown,
+			code:    errors.CodeUnknown,
+			reasons: []string{"reason#1", "reason 2"},
+			status:  codes.Unknown,
+		},
+		{
+			desc:    "error with kind without status code",
+			err:     errors.New("test error").WithKind(errors.KindUnprocessable),
+			kind:    errors.KindUnprocessable,
+			code:    errors.CodeUnknown,
+			reasons: []string{},
+			status:  codes.Unknown,
+		},
+		{
+			desc: "error with nested reasons",
+			err: errors.New("test error").
+				WithCause(errors.New("reason 1").WithCode("ERR_REASON").WithCause(errors.New("reason 2"))),
+			kind:    errors.KindUnknown,
+			code:    "ERR_REASON",
+			reasons: []string{"reason 1", "reason 2"},
+			status:  codes.Unknown,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, encoded := server.UnaryErrorHandler()(context.Background(), nil, nil, func(context.Context, any) (any, error) {
+				return nil, tc.err
+			})
+
+			invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				return encoded
+			}
+
+			err := interceptor.UnaryErrorHandler()(context.Background(), "/test", nil, nil, nil, invoker)
+			require.Error(t, err)
+
+			assert.Equal(t, "test error", err.Error())
+			assert.Equal(t, tc.kind, errors.Kind(err))
+			assert.Equal(t, tc.code, errors.Code(err))
+			assert.Equal(t, tc.retryable, errors.IsRetryable(err))
+			assert.Equal(t, tc.reasons, errors.SafeReasons(err))
+			assert.Equal(t, tc.status, status.Code(err))
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	t.Run("should map status without details", func(t *testing.T) {
+		err := interceptor.DecodeError(status.Error(codes.Unavailable, "connection refused"))
+
+		assert.Equal(t, "connection refused", err.Error())
+		assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err))
+		assert.Equal(t, errors.CodeUnknown, errors.Code(err))
+		assert.True(t, errors.IsRetryable(err))
+	})
+
+	t.Run("should keep attributes and causes", func(t *testing.T) {
+		_, encoded := server.UnaryErrorHandler()(context.Background(), nil, nil, func(context.Context, any) (any, error) {
+			return nil, errors.New("test error").
+				WithAttrs(errors.IntAttr("attempt", 2)).
+				WithCause(errors.New("reason").WithKind(errors.KindNotFound).WithCode("ERR_REASON"))
+		})
+
+		err := interceptor.DecodeError(encoded)
+
+		assert.Equal(t, errors.AttributeSet{"attempt": "2"}, errors.Attributes(err))
+		require.Len(t, errors.Unwrap(err), 3)
+		assert.Equal(t, errors.KindNotFound, errors.Kind(errors.Unwrap(err)[1]))
+		assert.Equal(t, errors.CodeType("ERR_REASON"), errors.Code(errors.Unwrap(err)[1]))
+	})
+
+	t.Run("should decode the error info of servers without the wire format", func(t *testing.T) {
+		st, err := status.New(codes.NotFound, "test error").WithDetails(&errdetails.ErrorInfo{
+			Metadata: map[string]string{"code": "ERR_TEST", "retryable": "true", "reasons": "8#reason 18#reason 2"},
+		})
+		require.NoError(t, err)
+
+		err = interceptor.DecodeError(st.Err())
+
+		assert.Equal(t, errors.KindNotFound, errors.Kind(err))
+		assert.Equal(t, errors.CodeType("ERR_TEST"), errors.Code(err))
+		assert.True(t, errors.IsRetryable(err))
+		assert.Equal(t, []string{"reason 1", "reason 2"}, errors.SafeReasons(err))
+	})
+
+	t.Run("should keep non status errors untouched", func(t *testing.T) {
+		assert.NoError(t, interceptor.DecodeError(nil))
+		assert.Equal(t, io.EOF, interceptor.DecodeError(io.EOF))
+	})
+}
@@ -0,0 +1,74 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/lcnascimento/go-kit/o11y/log"
+)
+
+var (
+	pkg    = "github.com/lcnascimento/go-kit/grpc/grpcclient/interceptor"
+	logger = log.MustNewLogger(pkg)
+)
+
+// UnaryLogging returns a new unary client interceptor suitable for request logging.
+func UnaryLogging() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		onCallEnd(ctx, cc, method, start, err)
+
+		return err
+	}
+}
+
+// StreamLogging returns a new stream client interceptor suitable for request logging.
+// Only the stream creation is logged, since its messages are exchanged after the interceptor returns.
+func StreamLogging() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+
+		onCallEnd(ctx, cc, method, start, err)
+
+		return cs, err
+	}
+}
+
+func onCallEnd(ctx context.Context, cc *grpc.ClientConn, method string, start time.Time, err error) {
+	latency := time.Since(start).String()
+
+	attrs := []log.Attr{
+		log.String("code", status.Code(err).String()),
+		log.String("latency", latency),
+	}
+
+	if cc != nil {
+		attrs = append(attrs, log.String("target", cc.Target()))
+	}
+
+	msg := fmt.Sprintf("RPC %s", method)
+	logger.Debug(ctx, msg, attrs...)
+}
@@ -0,0 +1,37 @@
+package grpcclient
+
+import (
+	"crypto/tls"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+type Option func(*config)
+
+func WithDialOpts(opts ...grpc.DialOption) Option {
+	return func(c *config) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	}
+}
+
+func WithOtelOpts(opts ...otelgrpc.Option) Option {
+	return func(c *config) {
+		c.otelOpts = append(c.otelOpts, opts...)
+	}
+}
+
+// WithTLSConfig secures the connection with the given TLS config, instead of the one built from env vars.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *config) {
+		c.tlsConfig = cfg
+		c.insecure = false
+	}
+}
+
+// WithInsecure disables transport security, regardless of the GRPC_CLIENT_INSECURE env var.
+func WithInsecure() Option {
+	return func(c *config) {
+		c.insecure = true
+	}
+}
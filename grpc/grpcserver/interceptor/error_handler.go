@@ -51,6 +51,8 @@ func StreamErrorHandler() grpc.StreamServerInterceptor {
 	}
 }
 
+// encodeError encodes err into a gRPC status error. Its details carry the error in its canonical protobuf wire format,
+// without the native Go errors of its causes, along with an ErrorInfo kept for clients decoding it by hand.
 func encodeError(err error) error {
 	kind := errors.Kind(err)
 	reasons := errors.SafeReasons(err)
@@ -66,7 +68,7 @@ func encodeError(err error) error {
 		details.Metadata["reasons"] = encodeReasons(reasons)
 	}
 
-	st, err := status.New(kindToGRPCStatusCode(kind), err.Error()).WithDetails(details)
+	st, err := status.New(kindToGRPCStatusCode(kind), err.Error()).WithDetails(details, errors.ToSafeProto(err))
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
@@ -100,6 +102,8 @@ func kindToGRPCStatusCode(kind errors.KindType) codes.Code {
 		return codes.Internal
 	case errors.KindResourceExhausted:
 		return codes.ResourceExhausted
+	case errors.KindServiceUnavailable:
+		return codes.Unavailable
 	default:
 		return codes.Unknown
 	}
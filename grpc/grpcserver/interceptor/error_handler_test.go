@@ -2,6 +2,7 @@ package interceptor_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/errors/errorspb"
 	"github.com/lcnascimento/go-kit/grpc/grpcserver/interceptor"
 )
 
@@ -44,6 +46,13 @@ func TestErrorHandler(t *testing.T) {
 			status:  codes.NotFound,
 			details: map[string]string{"code": "UNKNOWN", "retryable": "false"},
 		},
+		{
+			desc:    "error with kind service unavailable",
+			err:     errors.New("test error").WithKind(errors.KindServiceUnavailable).Retryable(),
+			message: "test error",
+			status:  codes.Unavailable,
+			details: map[string]string{"code": "UNKNOWN", "retryable": "true"},
+		},
 		{
 			desc:    "error with reasons",
 			err:     errors.New("test error").WithCause(errors.New("reason 1")).WithCause(errors.New("reason 2")),
@@ -70,12 +79,39 @@ func TestErrorHandler(t *testing.T) {
 			assert.Equal(t, tc.message, st.Message())
 
 			details := st.Details()
-			require.Len(t, details, 1)
+			require.Len(t, details, 2)
 
 			info, ok := details[0].(*errdetails.ErrorInfo)
 			require.True(t, ok)
 
 			assert.Equal(t, tc.details, info.Metadata)
+
+			encoded, ok := details[1].(*errorspb.Error)
+			require.True(t, ok)
+
+			decoded := errors.FromProto(encoded)
+			assert.Equal(t, errors.Kind(tc.err), errors.Kind(decoded))
+			assert.Equal(t, errors.Code(tc.err), errors.Code(decoded))
+			assert.Equal(t, errors.IsRetryable(tc.err), errors.IsRetryable(decoded))
+			assert.Equal(t, errors.Reasons(tc.err), errors.Reasons(decoded))
 		})
 	}
 }
+
+func TestErrorHandlerNativeCauses(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		cause := fmt.Errorf("query failed: %w", errors.New("user not found").WithKind(errors.KindNotFound))
+		return nil, errors.New("test error").WithKind(errors.KindInternal).WithCause(cause)
+	}
+
+	_, err := interceptor.UnaryErrorHandler()(context.Background(), nil, nil, handler)
+	require.Error(t, err)
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 2)
+
+	encoded, ok := details[1].(*errorspb.Error)
+	require.True(t, ok)
+
+	assert.Equal(t, []string{"user not found"}, errors.Reasons(errors.FromProto(encoded)), "should not expose native errors")
+}
@@ -0,0 +1,369 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+const (
+	defaultOAuth2ExpiryLeeway = 30 * time.Second
+	defaultOAuth2FetchTimeout = 30 * time.Second
+)
+
+// Authenticator attaches credentials to every request sent by the client. See [WithAuthenticator] and [Authentication].
+// Credentials are only set on the outgoing request, so they never reach span attributes or error attributes.
+type Authenticator interface {
+	Authenticate(ctx context.Context, request *http.Request) error
+}
+
+// Refresher is implemented by Authenticators whose credentials may be refreshed.
+// When a request is rejected as unauthenticated, the client refreshes the credentials and resends it once.
+// Refresh receives the rejected request, holding the rejected credentials, so that concurrent rejections of the
+// same credentials refresh them only once.
+type Refresher interface {
+	Refresh(ctx context.Context, rejected *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function into an Authenticator.
+type AuthenticatorFunc func(ctx context.Context, request *http.Request) error
+
+// Authenticate calls f(ctx, request).
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, request *http.Request) error {
+	return f(ctx, request)
+}
+
+// BearerToken authenticates requests with a static bearer token.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(_ context.Context, request *http.Request) error {
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// BasicAuth authenticates requests with HTTP basic authentication.
+func BasicAuth(username, password string) Authenticator {
+	return AuthenticatorFunc(func(_ context.Context, request *http.Request) error {
+		request.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// APIKeyHeader authenticates requests with an API key sent in the given header.
+func APIKeyHeader(header, key string) Authenticator {
+	return AuthenticatorFunc(func(_ context.Context, request *http.Request) error {
+		request.Header.Set(header, key)
+		return nil
+	})
+}
+
+// APIKeyQuery authenticates requests with an API key sent in the given query param.
+func APIKeyQuery(param, key string) Authenticator {
+	return AuthenticatorFunc(func(_ context.Context, request *http.Request) error {
+		query := request.URL.Query()
+		query.Set(param, key)
+		request.URL.RawQuery = query.Encode()
+
+		return nil
+	})
+}
+
+type authentication struct {
+	next Doer
+	auth Authenticator
+}
+
+// Authentication authenticates every request with the given Authenticator. When a request is rejected as
+// unauthenticated and the Authenticator is a [Refresher], the credentials are refreshed and the request is
+// resent once, as long as its body can be rewound.
+func Authentication(auth Authenticator) Middleware {
+	return func(next Doer) Doer {
+		return &authentication{next: next, auth: auth}
+	}
+}
+
+// Do sends the request with the credentials attached.
+func (a *authentication) Do(request *http.Request) (*http.Response, error) {
+	sent := request.Clone(request.Context())
+
+	res, err := a.send(sent)
+	if err != nil || !a.shouldReauthenticate(request, sent, res) {
+		return res, err
+	}
+
+	_ = res.Body.Close()
+
+	rewound, err := rewind(request.Context(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.send(rewound)
+}
+
+// send attaches the credentials to a copy of the original request and sends it.
+func (a *authentication) send(request *http.Request) (*http.Response, error) {
+	if err := a.auth.Authenticate(request.Context(), request); err != nil {
+		return nil, err
+	}
+
+	return a.next.Do(request)
+}
+
+// shouldReauthenticate reports whether the request was rejected as unauthenticated and the credentials
+// could be refreshed.
+func (a *authentication) shouldReauthenticate(request, sent *http.Request, res *http.Response) bool {
+	if res.StatusCode != http.StatusUnauthorized || callFrom(request).request.accepts(res.StatusCode) {
+		return false
+	}
+
+	refresher, ok := a.auth.(Refresher)
+	if !ok || !rewindable(request) {
+		return false
+	}
+
+	return refresher.Refresh(request.Context(), sent) == nil
+}
+
+type oauth2Config struct {
+	scopes       []string
+	params       map[string]string
+	expiryLeeway time.Duration
+	http         Doer
+}
+
+type OAuth2Option func(*oauth2Config)
+
+// WithOAuth2Scopes sets the scopes requested for the token.
+func WithOAuth2Scopes(scopes ...string) OAuth2Option {
+	return func(c *oauth2Config) {
+		c.scopes = append(c.scopes, scopes...)
+	}
+}
+
+// WithOAuth2Param adds an extra param to the token request, e.g. an audience.
+func WithOAuth2Param(key, value string) OAuth2Option {
+	return func(c *oauth2Config) {
+		c.params[key] = value
+	}
+}
+
+// WithOAuth2ExpiryLeeway sets how long before its expiry a token is refreshed. Defaults to 30 seconds.
+func WithOAuth2ExpiryLeeway(leeway time.Duration) OAuth2Option {
+	return func(c *oauth2Config) {
+		c.expiryLeeway = leeway
+	}
+}
+
+// WithOAuth2HTTPClient sets the HTTP client used to fetch tokens. Defaults to a new [http.Client].
+func WithOAuth2HTTPClient(client *http.Client) OAuth2Option {
+	return func(c *oauth2Config) {
+		c.http = client
+	}
+}
+
+type oauth2ClientCredentials struct {
+	cfg          oauth2Config
+	tokenURL     string
+	clientID     string
+	clientSecret string
+
+	mu        sync.Mutex
+	token     string
+	tokenType string
+	expiresAt time.Time
+	fetch     *oauth2Fetch
+}
+
+// oauth2Fetch is a token fetch in flight, awaited by the concurrent requests that need a new token.
+type oauth2Fetch struct {
+	done chan struct{}
+	err  error
+}
+
+type oauth2Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// OAuth2ClientCredentials authenticates requests with tokens fetched through the OAuth2 client credentials grant.
+// Tokens are cached and refreshed shortly before they expire, or when a request is rejected as unauthenticated.
+func OAuth2ClientCredentials(tokenURL, clientID, clientSecret string, opts ...OAuth2Option) Authenticator {
+	auth := &oauth2ClientCredentials{
+		cfg: oauth2Config{
+			params:       make(map[string]string),
+			expiryLeeway: defaultOAuth2ExpiryLeeway,
+			http:         &http.Client{Timeout: time.Second * defaultTimeoutInSeconds},
+		},
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+
+	for _, opt := range opts {
+		opt(&auth.cfg)
+	}
+
+	return auth
+}
+
+// Authenticate sets the cached token in the request, fetching a new one when it is about to expire.
+func (a *oauth2ClientCredentials) Authenticate(ctx context.Context, request *http.Request) error {
+	authorization, err := a.authorization(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", authorization)
+
+	return nil
+}
+
+// Refresh fetches a new token, unless the rejected token was already replaced by a concurrent refresh.
+func (a *oauth2ClientCredentials) Refresh(ctx context.Context, rejected *http.Request) error {
+	_, err := a.authorization(ctx, rejected.Header.Get("Authorization"))
+	return err
+}
+
+// authorization returns the Authorization header value of the cached token, fetching a new token when the cached
+// one is about to expire or was rejected. Concurrent fetches are merged into a single one, made without holding
+// the lock, which every request awaits until its own context is done.
+func (a *oauth2ClientCredentials) authorization(ctx context.Context, rejected string) (string, error) {
+	a.mu.Lock()
+
+	if authorization := a.tokenType + " " + a.token; !a.expired() && authorization != rejected {
+		a.mu.Unlock()
+		return authorization, nil
+	}
+
+	fetch := a.fetch
+	if fetch == nil {
+		fetch = &oauth2Fetch{done: make(chan struct{})}
+		a.fetch = fetch
+
+		go a.refresh(context.WithoutCancel(ctx), fetch)
+	}
+
+	a.mu.Unlock()
+
+	return a.await(ctx, fetch)
+}
+
+// refresh runs the merged fetch, storing the token it fetched. It does not stop with the request that started it,
+// which would fail the other ones awaiting it, so it is bounded by its own timeout instead.
+func (a *oauth2ClientCredentials) refresh(ctx context.Context, fetch *oauth2Fetch) {
+	ctx, cancel := context.WithTimeout(ctx, defaultOAuth2FetchTimeout)
+	defer cancel()
+
+	token, err := a.fetchToken(ctx)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err == nil {
+		a.store(token)
+	}
+
+	a.fetch, fetch.err = nil, err
+	close(fetch.done)
+}
+
+// await waits for the token fetch in flight, returning the token it fetched.
+func (a *oauth2ClientCredentials) await(ctx context.Context, fetch *oauth2Fetch) (string, error) {
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	if fetch.err != nil {
+		return "", fetch.err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.tokenType + " " + a.token, nil
+}
+
+func (a *oauth2ClientCredentials) expired() bool {
+	if a.token == "" {
+		return true
+	}
+
+	// tokens without an expiration are kept until a request is rejected as unauthenticated.
+	return !a.expiresAt.IsZero() && time.Now().After(a.expiresAt.Add(-a.cfg.expiryLeeway))
+}
+
+// store replaces the cached token.
+func (a *oauth2ClientCredentials) store(token *oauth2Token) {
+	a.token = token.AccessToken
+	a.tokenType = "Bearer"
+	a.expiresAt = time.Time{}
+
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		a.tokenType = token.TokenType
+	}
+
+	if token.ExpiresIn > 0 {
+		a.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+}
+
+func (a *oauth2ClientCredentials) fetchToken(ctx context.Context) (*oauth2Token, error) {
+	form := URL.Values{"grant_type": {"client_credentials"}}
+
+	if len(a.cfg.scopes) > 0 {
+		form.Set("scope", strings.Join(a.cfg.scopes, " "))
+	}
+
+	for key, value := range a.cfg.params {
+		form.Set(key, value)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, ErrFetchOAuth2Token.WithCause(err)
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", contentType)
+	request.SetBasicAuth(URL.QueryEscape(a.clientID), URL.QueryEscape(a.clientSecret))
+
+	res, err := a.cfg.http.Do(request)
+	if err != nil {
+		return nil, ErrFetchOAuth2Token.WithCause(err).Retryable()
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, ErrFetchOAuth2Token.WithCause(err).Retryable()
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		dErr := ErrFetchOAuth2Token.WithAttrs(errors.IntAttr("http.response.status_code", res.StatusCode))
+		if res.StatusCode >= http.StatusInternalServerError {
+			dErr = dErr.Retryable()
+		}
+
+		return nil, dErr
+	}
+
+	var token oauth2Token
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return nil, ErrFetchOAuth2Token.WithCause(errors.ErrCastPayload)
+	}
+
+	return &token, nil
+}
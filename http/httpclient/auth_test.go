@@ -0,0 +1,223 @@
+package httpclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	URL "net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tt := []struct {
+		desc   string
+		auth   httpclient.Authenticator
+		header string
+		value  string
+		query  string
+	}{
+		{
+			desc:   "should authenticate with a bearer token",
+			auth:   httpclient.BearerToken("secret"),
+			header: "Authorization",
+			value:  "Bearer secret",
+		},
+		{
+			desc:   "should authenticate with basic auth",
+			auth:   httpclient.BasicAuth("john", "secret"),
+			header: "Authorization",
+			value:  "Basic am9objpzZWNyZXQ=",
+		},
+		{
+			desc:   "should authenticate with an api key header",
+			auth:   httpclient.APIKeyHeader("X-API-Key", "secret"),
+			header: "X-API-Key",
+			value:  "secret",
+		},
+		{
+			desc:  "should authenticate with an api key query param",
+			auth:  httpclient.APIKeyQuery("api_key", "secret"),
+			query: "api_key=secret&page=2",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var received *http.Request
+
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				received = r
+			}))
+			defer server.Close()
+
+			client := httpclient.New(httpclient.WithAuthenticator(tc.auth))
+
+			_, err := client.Get(context.Background(), &httpclient.Request{
+				Host:        server.URL,
+				Path:        "/",
+				QueryParams: httpclient.QueryParams{"page": "2"},
+			})
+			require.NoError(t, err)
+
+			if tc.header != "" {
+				assert.Equal(t, tc.value, received.Header.Get(tc.header))
+			}
+
+			if tc.query != "" {
+				assert.Equal(t, tc.query, received.URL.RawQuery)
+			}
+		})
+	}
+}
+
+func TestAPIKeyQueryRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	server.Close()
+
+	client := httpclient.New(httpclient.WithAuthenticator(httpclient.APIKeyQuery("api_key", "secret")))
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/users"})
+	require.Error(t, err)
+
+	var urlErr *URL.Error
+	require.True(t, errors.As(err, &urlErr))
+
+	assert.Equal(t, server.URL+"/users", urlErr.URL)
+	assert.NotContains(t, fmt.Sprintf("%v", err), "secret")
+}
+
+// oauth2Server issues the tokens "token-1", "token-2", and so on, and serves the API, which only accepts the token
+// it currently holds.
+type oauth2Server struct {
+	tokens  *httptest.Server
+	api     *httptest.Server
+	fetches atomic.Int32
+	pending atomic.Int32
+	valid   atomic.Value
+	delay   time.Duration
+	status  int
+}
+
+func newOAuth2Server(t *testing.T) *oauth2Server {
+	t.Helper()
+
+	s := &oauth2Server{status: http.StatusOK}
+
+	s.tokens = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "secret" || r.FormValue("scope") != "read write" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.pending.Add(1)
+		time.Sleep(s.delay)
+
+		token := fmt.Sprintf("token-%d", s.fetches.Add(1))
+		s.valid.Store(token)
+
+		util.WriteResponse(w, s.status, map[string]any{"access_token": token, "token_type": "bearer", "expires_in": 3600})
+	}))
+	t.Cleanup(s.tokens.Close)
+
+	s.api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %v", s.valid.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(s.api.Close)
+
+	return s
+}
+
+func (s *oauth2Server) client() httpclient.Client {
+	return httpclient.New(httpclient.WithAuthenticator(
+		httpclient.OAuth2ClientCredentials(s.tokens.URL, "client", "secret", httpclient.WithOAuth2Scopes("read", "write")),
+	))
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	server := newOAuth2Server(t)
+	client := server.client()
+	request := func() error {
+		_, err := client.Get(context.Background(), &httpclient.Request{Host: server.api.URL, Path: "/"})
+		return err
+	}
+
+	require.NoError(t, request())
+	require.NoError(t, request())
+	assert.Equal(t, int32(1), server.fetches.Load(), "should cache the token")
+
+	server.valid.Store("revoked")
+
+	require.NoError(t, request())
+	assert.Equal(t, int32(2), server.fetches.Load(), "should refresh rejected tokens")
+}
+
+func TestOAuth2ClientCredentialsSingleFlight(t *testing.T) {
+	server := newOAuth2Server(t)
+	server.delay = 50 * time.Millisecond
+
+	client := server.client()
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.Get(context.Background(), &httpclient.Request{Host: server.api.URL, Path: "/"})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), server.fetches.Load())
+}
+
+func TestOAuth2ClientCredentialsCanceledFetch(t *testing.T) {
+	server := newOAuth2Server(t)
+	server.delay = 50 * time.Millisecond
+
+	client := server.client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	canceled := make(chan error, 1)
+
+	go func() {
+		_, err := client.Get(ctx, &httpclient.Request{Host: server.api.URL, Path: "/"})
+		canceled <- err
+	}()
+
+	require.Eventually(t, func() bool { return server.pending.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.api.URL, Path: "/"})
+
+	require.NoError(t, err, "should not fail the requests awaiting the fetch started by a canceled one")
+	assert.Error(t, <-canceled, "should stop the canceled request")
+	assert.Equal(t, int32(1), server.fetches.Load())
+}
+
+func TestOAuth2ClientCredentialsFailure(t *testing.T) {
+	server := newOAuth2Server(t)
+	server.status = http.StatusInternalServerError
+
+	_, err := server.client().Get(context.Background(), &httpclient.Request{Host: server.api.URL, Path: "/"})
+
+	assert.Equal(t, errors.Code(httpclient.ErrFetchOAuth2Token), errors.Code(err))
+	assert.True(t, errors.IsRetryable(err))
+}
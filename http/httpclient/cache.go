@@ -0,0 +1,396 @@
+package httpclient
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheStatus string
+
+const (
+	cacheHit         cacheStatus = "hit"
+	cacheMiss        cacheStatus = "miss"
+	cacheRevalidated cacheStatus = "revalidated"
+)
+
+// cacheableStatusCodes are the response status codes that may be cached, as defined by RFC 9110.
+var cacheableStatusCodes = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// safeMethods are the HTTP methods that do not invalidate cached responses.
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// CachedResponse is a response stored in a CacheStore.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Vary holds the values of the request headers listed by the response Vary header.
+	Vary map[string]string
+
+	// ExpiresAt is the time the response becomes stale. Stale responses are revalidated before being used.
+	ExpiresAt time.Time
+}
+
+// CacheStore stores the responses cached by [Cache]. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool)
+	Set(ctx context.Context, key string, response *CachedResponse)
+	Delete(ctx context.Context, key string)
+}
+
+type cache struct {
+	next  Doer
+	store CacheStore
+}
+
+// Cache caches the responses of GET requests in the given store, honouring their Cache-Control and Expires headers.
+// Fresh responses are served from the store, while stale ones are revalidated with their ETag and Last-Modified
+// validators. Responses without explicit freshness are only cached when they have validators.
+//
+// It behaves as a private cache, keyed by the request URL, so it must not be shared by clients sending
+// requests on behalf of different users. Unsafe requests, like POST, invalidate the response cached for their URL.
+// Streamed requests, and the ones sent with Cache-Control: no-store or conditional headers, bypass the cache.
+func Cache(store CacheStore) Middleware {
+	return func(next Doer) Doer {
+		return &cache{next: next, store: store}
+	}
+}
+
+// Do serves the request from the cache when possible, sending it otherwise.
+func (c *cache) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	key := request.URL.String()
+
+	if request.Method != http.MethodGet {
+		res, err := c.next.Do(request)
+		if err == nil && !safeMethods[request.Method] && res.StatusCode < http.StatusBadRequest {
+			c.store.Delete(ctx, key)
+		}
+
+		return res, err
+	}
+
+	directives := parseCacheControl(request.Header)
+	if _, noStore := directives["no-store"]; noStore || callFrom(request).stream || isConditional(request) {
+		return c.next.Do(request)
+	}
+
+	stored, ok := c.store.Get(ctx, key)
+	if ok && !stored.matches(request) {
+		stored, ok = nil, false
+	}
+
+	if ok && stored.fresh(time.Now()) && !revalidationRequired(directives) {
+		c.onLookup(ctx, request, cacheHit)
+		return stored.response(request), nil
+	}
+
+	sent := request
+	if ok {
+		sent = stored.conditional(request)
+	}
+
+	res, err := c.next.Do(sent)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && res.StatusCode == http.StatusNotModified {
+		_ = res.Body.Close()
+
+		// the merged headers may forbid storing the response from now on, e.g. with Cache-Control: no-store.
+		revalidated, cacheable := stored.revalidate(res.Header, time.Now())
+		if cacheable {
+			c.store.Set(ctx, key, revalidated)
+		} else {
+			c.store.Delete(ctx, key)
+		}
+
+		c.onLookup(ctx, request, cacheRevalidated)
+
+		return revalidated.response(request), nil
+	}
+
+	c.onLookup(ctx, request, cacheMiss)
+
+	return c.save(ctx, key, request, res)
+}
+
+// save stores the response when it is cacheable, buffering its body.
+func (c *cache) save(ctx context.Context, key string, request *http.Request, res *http.Response) (*http.Response, error) {
+	expiresAt, ok := cacheExpiry(res.Header, time.Now())
+	if !ok || !cacheableStatusCodes[res.StatusCode] {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.store.Set(ctx, key, &CachedResponse{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+		Vary:       varyValues(request, res.Header),
+		ExpiresAt:  expiresAt,
+	})
+
+	return res, nil
+}
+
+func (r *CachedResponse) fresh(now time.Time) bool {
+	return now.Before(r.ExpiresAt)
+}
+
+// matches reports whether the request headers listed by the response Vary header hold the same values.
+func (r *CachedResponse) matches(request *http.Request) bool {
+	for name, value := range r.Vary {
+		if request.Header.Get(name) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conditional returns a copy of the request that revalidates the response with its validators.
+func (r *CachedResponse) conditional(request *http.Request) *http.Request {
+	conditional := request.Clone(request.Context())
+
+	if etag := r.Header.Get("ETag"); etag != "" {
+		conditional.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := r.Header.Get("Last-Modified"); lastModified != "" {
+		conditional.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return conditional
+}
+
+// revalidate returns a copy of the response updated with the headers of a 304 Not Modified response,
+// reporting whether it may still be cached.
+func (r *CachedResponse) revalidate(header http.Header, now time.Time) (*CachedResponse, bool) {
+	revalidated := *r
+	revalidated.Header = r.Header.Clone()
+
+	for name, values := range header {
+		if name == "Content-Length" {
+			continue
+		}
+
+		revalidated.Header[name] = values
+	}
+
+	expiresAt, cacheable := cacheExpiry(revalidated.Header, now)
+	revalidated.ExpiresAt = expiresAt
+
+	return &revalidated, cacheable
+}
+
+func (r *CachedResponse) response(request *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       request,
+	}
+}
+
+// cacheExpiry computes when a response becomes stale, reporting whether it may be cached at all.
+func cacheExpiry(header http.Header, now time.Time) (time.Time, bool) {
+	directives := parseCacheControl(header)
+	if _, noStore := directives["no-store"]; noStore || header.Get("Vary") == "*" {
+		return time.Time{}, false
+	}
+
+	validators := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+
+	if _, noCache := directives["no-cache"]; noCache {
+		return time.Time{}, validators
+	}
+
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return time.Time{}, validators
+		}
+
+		age, _ := strconv.Atoi(header.Get("Age"))
+		expiresAt := now.Add(time.Duration(seconds-age) * time.Second)
+
+		return expiresAt, validators || expiresAt.After(now)
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return time.Time{}, validators
+		}
+
+		if date, err := http.ParseTime(header.Get("Date")); err == nil {
+			expiresAt = now.Add(expiresAt.Sub(date))
+		}
+
+		return expiresAt, validators || expiresAt.After(now)
+	}
+
+	return time.Time{}, validators
+}
+
+// parseCacheControl parses the Cache-Control header into its lower cased directives and their values.
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+
+	for _, value := range header.Values("Cache-Control") {
+		for directive := range strings.SplitSeq(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+
+			directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+
+	return directives
+}
+
+// revalidationRequired reports whether the request directives forbid serving a cached response without revalidating it.
+func revalidationRequired(directives map[string]string) bool {
+	if _, noCache := directives["no-cache"]; noCache {
+		return true
+	}
+
+	return directives["max-age"] == "0"
+}
+
+// isConditional reports whether the request already holds validators set by the caller.
+func isConditional(request *http.Request) bool {
+	return request.Header.Get("If-None-Match") != "" || request.Header.Get("If-Modified-Since") != ""
+}
+
+func varyValues(request *http.Request, header http.Header) map[string]string {
+	values := make(map[string]string)
+
+	for _, vary := range header.Values("Vary") {
+		for name := range strings.SplitSeq(vary, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				values[name] = request.Header.Get(name)
+			}
+		}
+	}
+
+	return values
+}
+
+type memoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type memoryCacheEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewMemoryCache returns an in-memory CacheStore that keeps up to maxEntries responses, evicting the least
+// recently used ones. A non positive maxEntries keeps every response.
+func NewMemoryCache(maxEntries int) CacheStore {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get retrieves the response stored under the given key.
+func (m *memoryCache) Get(_ context.Context, key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+
+	entry, _ := element.Value.(*memoryCacheEntry)
+
+	return entry.response, true
+}
+
+// Set stores the response under the given key, evicting the least recently used response when the cache is full.
+func (m *memoryCache) Set(_ context.Context, key string, response *CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		entry, _ := element.Value.(*memoryCacheEntry)
+		entry.response = response
+		m.order.MoveToFront(element)
+
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryCacheEntry{key: key, response: response})
+
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+
+		entry, _ := oldest.Value.(*memoryCacheEntry)
+		delete(m.entries, entry.key)
+	}
+}
+
+// Delete removes the response stored under the given key.
+func (m *memoryCache) Delete(_ context.Context, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.order.Remove(element)
+		delete(m.entries, key)
+	}
+}
@@ -0,0 +1,190 @@
+package httpclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+// versioned serves the version of its resource, bumped by every unsafe request, with the given headers.
+// Conditional requests holding the current ETag are answered with 304 Not Modified and the revalidation headers,
+// counted by revalidations.
+func versioned(headers, revalidation http.Header, revalidations *atomic.Int32) serverHandler {
+	var version atomic.Int32
+
+	return func(w http.ResponseWriter, r *http.Request, _ int32) {
+		if r.Method != http.MethodGet {
+			version.Add(1)
+			return
+		}
+
+		etag := fmt.Sprintf(`"v%d"`, version.Load())
+		w.Header().Set("ETag", etag)
+
+		if r.Header.Get("If-None-Match") == etag {
+			for name, values := range revalidation {
+				w.Header()[name] = values
+			}
+
+			revalidations.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		for name, values := range headers {
+			w.Header()[name] = values
+		}
+
+		_, _ = fmt.Fprintf(w, "v%d-%s", version.Load(), r.Header.Get("Accept-Language"))
+	}
+}
+
+func TestCache(t *testing.T) {
+	type step struct {
+		method        string
+		language      string
+		body          string
+		requests      int32
+		revalidations int32
+	}
+
+	tt := []struct {
+		desc         string
+		headers      http.Header
+		revalidation http.Header
+		steps        []step
+	}{
+		{
+			desc:    "should serve fresh responses from the cache",
+			headers: http.Header{"Cache-Control": {"max-age=60"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{body: "v0-", requests: 1},
+			},
+		},
+		{
+			desc:    "should not cache no-store responses",
+			headers: http.Header{"Cache-Control": {"no-store"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{body: "v0-", requests: 2},
+			},
+		},
+		{
+			desc:    "should revalidate stale responses",
+			headers: http.Header{"Cache-Control": {"no-cache"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{body: "v0-", requests: 2, revalidations: 1},
+				{method: http.MethodPut, requests: 3, revalidations: 1},
+				{body: "v1-", requests: 4, revalidations: 1},
+			},
+		},
+		{
+			desc:    "should invalidate responses on unsafe requests",
+			headers: http.Header{"Cache-Control": {"max-age=60"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{method: http.MethodPost, requests: 2},
+				{body: "v1-", requests: 3},
+				{body: "v1-", requests: 3},
+			},
+		},
+		{
+			desc:         "should refresh the freshness of revalidated responses",
+			headers:      http.Header{"Cache-Control": {"no-cache"}},
+			revalidation: http.Header{"Cache-Control": {"max-age=60"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{body: "v0-", requests: 2, revalidations: 1},
+				{body: "v0-", requests: 2, revalidations: 1},
+			},
+		},
+		{
+			desc:         "should stop caching responses revalidated as no-store",
+			headers:      http.Header{"Cache-Control": {"no-cache"}},
+			revalidation: http.Header{"Cache-Control": {"no-store"}},
+			steps: []step{
+				{body: "v0-", requests: 1},
+				{body: "v0-", requests: 2, revalidations: 1},
+				{body: "v0-", requests: 3, revalidations: 1},
+			},
+		},
+		{
+			desc:    "should honour the vary header",
+			headers: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Language"}},
+			steps: []step{
+				{language: "en", body: "v0-en", requests: 1},
+				{language: "en", body: "v0-en", requests: 1},
+				{language: "pt", body: "v0-pt", requests: 2},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var revalidations atomic.Int32
+			server, requests := newServer(t, versioned(tc.headers, tc.revalidation, &revalidations))
+
+			client := httpclient.New(httpclient.WithCache(httpclient.NewMemoryCache(10)))
+
+			for i, s := range tc.steps {
+				request := &httpclient.Request{Host: server.URL, Path: "/", Headers: httpclient.Headers{"Accept-Language": s.language}}
+
+				var (
+					res httpclient.Result
+					err error
+				)
+
+				switch s.method {
+				case http.MethodPut:
+					res, err = client.Put(context.Background(), request)
+				case http.MethodPost:
+					res, err = client.Post(context.Background(), request)
+				default:
+					res, err = client.Get(context.Background(), request)
+				}
+
+				require.NoError(t, err, "step %d", i)
+				assert.Equal(t, s.body, string(res.Response), "step %d", i)
+				assert.Equal(t, s.requests, requests.Load(), "step %d", i)
+				assert.Equal(t, s.revalidations, revalidations.Load(), "step %d", i)
+			}
+		})
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	store := httpclient.NewMemoryCache(2)
+
+	store.Set(ctx, "a", &httpclient.CachedResponse{Body: []byte("a")})
+	store.Set(ctx, "b", &httpclient.CachedResponse{Body: []byte("b")})
+
+	_, ok := store.Get(ctx, "a")
+	require.True(t, ok)
+
+	store.Set(ctx, "c", &httpclient.CachedResponse{Body: []byte("c")})
+
+	_, ok = store.Get(ctx, "b")
+	assert.False(t, ok, "should evict the least recently used response")
+
+	for _, key := range []string{"a", "c"} {
+		stored, ok := store.Get(ctx, key)
+		require.True(t, ok)
+		assert.Equal(t, key, string(stored.Body))
+	}
+
+	store.Delete(ctx, "a")
+
+	_, ok = store.Get(ctx, "a")
+	assert.False(t, ok)
+}
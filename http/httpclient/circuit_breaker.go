@@ -0,0 +1,331 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	oMetric "go.opentelemetry.io/otel/metric"
+
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+const (
+	defaultBreakerConsecutiveFailures = 5
+	defaultBreakerFailureRatio        = 0.5
+	defaultBreakerMinRequests         = 10
+	defaultBreakerWindow              = time.Minute
+	defaultBreakerCoolDown            = 30 * time.Second
+	defaultBreakerHalfOpenRequests    = 1
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half-open"
+	case breakerOpen:
+		return "open"
+	}
+
+	return "unknown"
+}
+
+type breakerOutcome int
+
+const (
+	outcomeSuccess breakerOutcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+type breakerConfig struct {
+	consecutiveFailures int
+	failureRatio        float64
+	minRequests         int
+	window              time.Duration
+	coolDown            time.Duration
+	halfOpenRequests    int
+}
+
+// circuitBreaker keeps an independent circuit for each host the client sends requests to.
+//
+// A closed circuit lets every request through, counting failures within a rolling window. It opens once
+// the consecutive failures or the failure ratio thresholds are reached. An open circuit fails fast with
+// [ErrCircuitOpen] until the cool-down elapses, moving to half-open. A half-open circuit lets a limited number
+// of trial requests through, closing on success and opening again on failure.
+type circuitBreaker struct {
+	next Doer
+	cfg  breakerConfig
+
+	mu        sync.Mutex
+	hosts     map[string]*hostCircuit
+	lastSweep time.Time
+
+	registration oMetric.Registration
+}
+
+type hostCircuit struct {
+	state               breakerState
+	requests            int
+	failures            int
+	consecutiveFailures int
+	windowStart         time.Time
+	openedAt            time.Time
+	halfOpenInFlight    int
+	lastUsed            time.Time
+
+	// generation is incremented on every state transition, so that the outcomes of requests
+	// admitted under a previous state are ignored.
+	generation uint64
+}
+
+// admission is the state of the circuit a request was admitted under.
+type admission struct {
+	generation uint64
+}
+
+// CircuitBreaker keeps a circuit breaker for each host requests are sent to.
+// While the circuit of a host is open, its requests fail fast with [ErrCircuitOpen].
+// The state of the circuits is reported as a metric until the client is closed.
+func CircuitBreaker(opts ...CircuitBreakerOption) Middleware {
+	return func(next Doer) Doer {
+		cb := newCircuitBreaker(opts...)
+		cb.next = next
+
+		return cb
+	}
+}
+
+func newCircuitBreaker(opts ...CircuitBreakerOption) *circuitBreaker {
+	cb := &circuitBreaker{
+		cfg: breakerConfig{
+			consecutiveFailures: defaultBreakerConsecutiveFailures,
+			failureRatio:        defaultBreakerFailureRatio,
+			minRequests:         defaultBreakerMinRequests,
+			window:              defaultBreakerWindow,
+			coolDown:            defaultBreakerCoolDown,
+			halfOpenRequests:    defaultBreakerHalfOpenRequests,
+		},
+		hosts:     make(map[string]*hostCircuit),
+		lastSweep: time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(&cb.cfg)
+	}
+
+	cb.registration = cb.onCreate()
+
+	return cb
+}
+
+// Close stops reporting the state of the circuits.
+func (cb *circuitBreaker) Close() error {
+	if cb.registration == nil {
+		return nil
+	}
+
+	return cb.registration.Unregister()
+}
+
+// Do sends the request, unless the circuit of its host is open.
+func (cb *circuitBreaker) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	host := request.URL.Host
+
+	adm, err := cb.allow(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cb.next.Do(request)
+	cb.done(ctx, host, adm, breakerOutcomeOf(ctx, res, err))
+
+	return res, err
+}
+
+// allow reports whether a request to the given host may be sent, failing fast with ErrCircuitOpen otherwise.
+// Every allowed request must be followed by a call to done, along with the admission it was allowed under.
+func (cb *circuitBreaker) allow(ctx context.Context, host string) (admission, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+	circuit := cb.circuit(host, now)
+
+	switch circuit.state {
+	case breakerClosed:
+		if cb.cfg.window > 0 && now.Sub(circuit.windowStart) >= cb.cfg.window {
+			circuit.reset(now)
+		}
+
+		return admission{generation: circuit.generation}, nil
+	case breakerOpen:
+		if now.Sub(circuit.openedAt) < cb.cfg.coolDown {
+			return admission{}, ErrCircuitOpen.WithAttribute("server.address", host)
+		}
+
+		cb.transition(ctx, host, circuit, breakerHalfOpen, now)
+	case breakerHalfOpen:
+	}
+
+	if circuit.halfOpenInFlight >= cb.cfg.halfOpenRequests {
+		return admission{}, ErrCircuitOpen.WithAttribute("server.address", host)
+	}
+
+	circuit.halfOpenInFlight++
+
+	return admission{generation: circuit.generation}, nil
+}
+
+// done records the result of a request allowed by allow. Results of requests admitted before the last
+// state transition are ignored, so that only trial requests decide whether a half-open circuit closes.
+func (cb *circuitBreaker) done(ctx context.Context, host string, adm admission, outcome breakerOutcome) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+
+	circuit := cb.circuit(host, now)
+	if circuit.generation != adm.generation {
+		return
+	}
+
+	switch circuit.state {
+	case breakerHalfOpen:
+		circuit.halfOpenInFlight = max(circuit.halfOpenInFlight-1, 0)
+
+		switch outcome {
+		case outcomeFailure:
+			cb.transition(ctx, host, circuit, breakerOpen, now)
+		case outcomeSuccess:
+			cb.transition(ctx, host, circuit, breakerClosed, now)
+		case outcomeIgnored:
+		}
+	case breakerClosed:
+		if outcome == outcomeIgnored {
+			return
+		}
+
+		circuit.requests++
+
+		if outcome == outcomeFailure {
+			circuit.failures++
+			circuit.consecutiveFailures++
+		} else {
+			circuit.consecutiveFailures = 0
+		}
+
+		if cb.shouldTrip(circuit) {
+			cb.transition(ctx, host, circuit, breakerOpen, now)
+		}
+	case breakerOpen:
+	}
+}
+
+func (cb *circuitBreaker) shouldTrip(circuit *hostCircuit) bool {
+	if cb.cfg.consecutiveFailures > 0 && circuit.consecutiveFailures >= cb.cfg.consecutiveFailures {
+		return true
+	}
+
+	if cb.cfg.failureRatio <= 0 || circuit.requests < cb.cfg.minRequests {
+		return false
+	}
+
+	return float64(circuit.failures)/float64(circuit.requests) >= cb.cfg.failureRatio
+}
+
+func (cb *circuitBreaker) circuit(host string, now time.Time) *hostCircuit {
+	if now.Sub(cb.lastSweep) >= cb.idleTimeout() {
+		cb.sweep(now)
+	}
+
+	circuit, ok := cb.hosts[host]
+	if !ok {
+		circuit = &hostCircuit{windowStart: now}
+		cb.hosts[host] = circuit
+	}
+
+	circuit.lastUsed = now
+
+	return circuit
+}
+
+// sweep evicts the closed circuits idle for longer than the idle timeout, so that clients sending requests
+// to many hosts do not keep a circuit for each of them forever.
+func (cb *circuitBreaker) sweep(now time.Time) {
+	cb.lastSweep = now
+
+	for host, circuit := range cb.hosts {
+		if circuit.state == breakerClosed && now.Sub(circuit.lastUsed) >= cb.idleTimeout() {
+			delete(cb.hosts, host)
+		}
+	}
+}
+
+// idleTimeout is the window, as closed circuits idle for longer than it hold no failures worth keeping.
+// The default window is used when windows are disabled.
+func (cb *circuitBreaker) idleTimeout() time.Duration {
+	if cb.cfg.window > 0 {
+		return cb.cfg.window
+	}
+
+	return defaultBreakerWindow
+}
+
+func (cb *circuitBreaker) transition(ctx context.Context, host string, circuit *hostCircuit, to breakerState, now time.Time) {
+	from := circuit.state
+
+	circuit.state = to
+	circuit.generation++
+	circuit.halfOpenInFlight = 0
+	circuit.reset(now)
+
+	if to == breakerOpen {
+		circuit.openedAt = now
+	}
+
+	cb.onStateChange(ctx, host, from, to)
+}
+
+func (c *hostCircuit) reset(now time.Time) {
+	c.requests = 0
+	c.failures = 0
+	c.consecutiveFailures = 0
+	c.windowStart = now
+}
+
+// breakerOutcomeOf classifies a request result. Only failures that point to an unhealthy host, like transport
+// errors, 429 and 5xx responses, count as failures. Canceled requests are ignored.
+func breakerOutcomeOf(ctx context.Context, res *http.Response, err error) breakerOutcome {
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, errors.ErrContextCanceled) {
+			return outcomeIgnored
+		}
+
+		// errors raised by the client itself, like authentication failures, only count when retryable.
+		var cErr errors.CustomError
+		if errors.As(err, &cErr) && !errors.IsRetryable(err) {
+			return outcomeSuccess
+		}
+
+		return outcomeFailure
+	}
+
+	if res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests {
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
@@ -0,0 +1,210 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		status int
+		wait   time.Duration
+		open   bool
+	}
+
+	tt := []struct {
+		desc  string
+		opts  []httpclient.CircuitBreakerOption
+		steps []step
+	}{
+		{
+			desc: "should open after consecutive failures",
+			opts: []httpclient.CircuitBreakerOption{httpclient.WithBreakerConsecutiveFailures(2)},
+			steps: []step{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK, open: true},
+			},
+		},
+		{
+			desc: "should reset consecutive failures on success",
+			opts: []httpclient.CircuitBreakerOption{httpclient.WithBreakerConsecutiveFailures(2)},
+			steps: []step{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK},
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK},
+			},
+		},
+		{
+			desc: "should not count client errors as failures",
+			opts: []httpclient.CircuitBreakerOption{httpclient.WithBreakerConsecutiveFailures(1)},
+			steps: []step{
+				{status: http.StatusNotFound},
+				{status: http.StatusOK},
+			},
+		},
+		{
+			desc: "should open when the failure ratio is reached",
+			opts: []httpclient.CircuitBreakerOption{
+				httpclient.WithBreakerConsecutiveFailures(0),
+				httpclient.WithBreakerFailureRatio(0.5, 4),
+			},
+			steps: []step{
+				{status: http.StatusInternalServerError},
+				{status: http.StatusOK},
+				{status: http.StatusTooManyRequests},
+				{status: http.StatusOK},
+				{status: http.StatusOK, open: true},
+			},
+		},
+		{
+			desc: "should close when the trial request succeeds",
+			opts: []httpclient.CircuitBreakerOption{
+				httpclient.WithBreakerConsecutiveFailures(1),
+				httpclient.WithBreakerCoolDown(20 * time.Millisecond),
+			},
+			steps: []step{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK, open: true},
+				{status: http.StatusOK, wait: 30 * time.Millisecond},
+				{status: http.StatusOK},
+			},
+		},
+		{
+			desc: "should open again when the trial request fails",
+			opts: []httpclient.CircuitBreakerOption{
+				httpclient.WithBreakerConsecutiveFailures(1),
+				httpclient.WithBreakerCoolDown(20 * time.Millisecond),
+			},
+			steps: []step{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable, wait: 30 * time.Millisecond},
+				{status: http.StatusOK, open: true},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var status atomic.Int32
+			server, requests := newServer(t, respond(&status))
+
+			client := httpclient.New(httpclient.WithCircuitBreaker(tc.opts...))
+			defer func() { assert.NoError(t, client.(io.Closer).Close()) }()
+
+			for i, s := range tc.steps {
+				time.Sleep(s.wait)
+				status.Store(int32(s.status))
+
+				sent := requests.Load()
+				_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+
+				if s.open {
+					assert.Equal(t, errors.Code(httpclient.ErrCircuitOpen), errors.Code(err), "step %d", i)
+					assert.Equal(t, sent, requests.Load(), "step %d should fail fast", i)
+				} else {
+					assert.NotEqual(t, errors.Code(httpclient.ErrCircuitOpen), errors.Code(err), "step %d", i)
+					assert.Equal(t, sent+1, requests.Load(), "step %d should be sent", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerPerHost(t *testing.T) {
+	failing, _ := newServer(t, sequence(http.StatusServiceUnavailable))
+	healthy, _ := newServer(t, sequence(http.StatusOK))
+
+	client := httpclient.New(httpclient.WithCircuitBreaker(httpclient.WithBreakerConsecutiveFailures(1)))
+	defer client.(io.Closer).Close()
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: failing.URL, Path: "/"})
+	require.Error(t, err)
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: failing.URL, Path: "/"})
+	assert.Equal(t, errors.Code(httpclient.ErrCircuitOpen), errors.Code(err))
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: healthy.URL, Path: "/"})
+	assert.NoError(t, err)
+}
+
+func TestCircuitBreakerStopsRetries(t *testing.T) {
+	server, requests := newServer(t, sequence(http.StatusServiceUnavailable))
+
+	client := httpclient.New(
+		httpclient.WithRetry(fastRetry...),
+		httpclient.WithCircuitBreaker(httpclient.WithBreakerConsecutiveFailures(1)),
+	)
+	defer client.(io.Closer).Close()
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+
+	assert.Equal(t, errors.Code(httpclient.ErrCircuitOpen), errors.Code(err))
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+// circuitStates returns the reported state of the circuit of each host.
+func circuitStates(t *testing.T) map[string]float64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(context.Background(), &rm))
+
+	states := make(map[string]float64)
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			data, ok := m.Data.(metricdata.Gauge[float64])
+			if m.Name != "http.client.circuit_breaker.state" || !ok {
+				continue
+			}
+
+			for _, dp := range data.DataPoints {
+				host, _ := dp.Attributes.Value("server.address")
+				states[host.AsString()] = dp.Value
+			}
+		}
+	}
+
+	return states
+}
+
+func TestCircuitBreakerEviction(t *testing.T) {
+	idle, _ := newServer(t, sequence(http.StatusOK))
+	failing, _ := newServer(t, sequence(http.StatusServiceUnavailable))
+
+	client := httpclient.New(httpclient.WithCircuitBreaker(
+		httpclient.WithBreakerConsecutiveFailures(1),
+		httpclient.WithBreakerWindow(20*time.Millisecond),
+	))
+	defer client.(io.Closer).Close()
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: idle.URL, Path: "/"})
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: failing.URL, Path: "/"})
+	require.Error(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: failing.URL, Path: "/"})
+	assert.Equal(t, errors.Code(httpclient.ErrCircuitOpen), errors.Code(err))
+
+	states := circuitStates(t)
+
+	assert.NotContains(t, states, strings.TrimPrefix(idle.URL, "http://"), "should evict idle closed circuits")
+	assert.Equal(t, float64(2), states[strings.TrimPrefix(failing.URL, "http://")], "should keep open circuits")
+}
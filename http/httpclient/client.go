@@ -5,15 +5,14 @@ import (
 	"context"
 	"io"
 	"net/http"
-	"strconv"
-	"strings"
+	"sync"
 	"time"
 
-	"go.opentelemetry.io/otel/trace"
-
 	URL "net/url"
 
 	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/o11y/redact"
+	"github.com/lcnascimento/go-kit/retry"
 )
 
 const (
@@ -21,26 +20,139 @@ const (
 	contentType             = "application/json"
 )
 
-type httpClientProvider interface {
-	Do(request *http.Request) (*http.Response, error)
-}
-
 type client struct {
-	http    httpClientProvider
-	timeout time.Duration
+	http      Doer
+	doer      Doer
+	timeout   time.Duration
+	attempt   time.Duration
+	redactor  *redact.Redactor
+	transport transportConfig
+
+	cache       Middleware
+	retry       bool
+	retryOpts   []retry.Option
+	breaker     Middleware
+	auth        Middleware
+	middlewares []Middleware
+	chain       []Middleware
+	closers     []io.Closer
 }
 
+// New creates a new Client with the given options.
+//
+// Unless a custom transport is set by [WithTransport], mutual TLS is driven by the following env vars:
+//   - HTTP_CLIENT_TLS_CA_FILE: PEM encoded CA certificates used to verify servers. Defaults to the system pool.
+//   - HTTP_CLIENT_TLS_CERT_FILE and HTTP_CLIENT_TLS_KEY_FILE: client certificate for mutual TLS.
+//
+// When the certificates can not be loaded, the failure is logged and every request fails with
+// [ErrLoadTLSCredentials]. See [NewE] to handle it on creation instead.
+//
+// The Client also implements [io.Closer], releasing the resources it holds once it is no longer needed:
+//
+//	client := httpclient.New()
+//	defer client.(io.Closer).Close()
 func New(opts ...Option) Client {
+	client, err := newClient(opts...)
+	if err != nil {
+		client.onTransportError(err)
+	}
+
+	return client
+}
+
+// NewE creates a new Client like [New], failing with [ErrLoadTLSCredentials] when the certificates of its
+// transport can not be loaded.
+func NewE(opts ...Option) (Client, error) {
+	client, err := newClient(opts...)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// newClient creates a new client, along with the error that prevented its transport from being built, if any.
+// Such client fails every request with the error.
+func newClient(opts ...Option) (*client, error) {
 	client := &client{
-		http:    &http.Client{},
-		timeout: time.Second * defaultTimeoutInSeconds,
+		http:      &http.Client{},
+		timeout:   time.Second * defaultTimeoutInSeconds,
+		redactor:  redact.Default(),
+		transport: defaultTransportConfig(),
 	}
 
 	for _, opt := range opts {
 		opt(client)
 	}
 
-	return client
+	var transportErr error
+
+	if httpClient, ok := client.http.(*http.Client); ok && httpClient.Transport == nil {
+		transport, err := newTransport(client.transport)
+		if err != nil {
+			transport, transportErr = &failedTransport{err: err}, err
+		}
+
+		httpClient.Transport = transport
+	}
+
+	// the client timeout bounds the whole call, retries included, while the attempt timeout bounds each attempt.
+	doer, closers := chain(&timeouter{
+		next:    &errorRedactor{next: client.http, redactor: client.redactor},
+		timeout: client.attempt,
+		attempt: true,
+	}, client.middlewareChain()...)
+
+	client.doer, client.closers = &timeouter{next: doer, timeout: client.timeout}, closers
+
+	return client, transportErr
+}
+
+// Close releases the resources held by the client, like the metric callbacks of its circuit breaker and the idle
+// connections of its transport. Requests in flight are not interrupted.
+func (c *client) Close() error {
+	var err error
+
+	for _, closer := range c.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	if httpClient, ok := c.http.(*http.Client); ok {
+		httpClient.CloseIdleConnections()
+	}
+
+	return err
+}
+
+// middlewareChain returns the middlewares every request is sent through, from the outermost to the innermost.
+func (c *client) middlewareChain() []Middleware {
+	if c.chain != nil {
+		return c.chain
+	}
+
+	var mws []Middleware
+
+	if c.cache != nil {
+		mws = append(mws, c.cache)
+	}
+
+	// the retry middleware is always part of the default chain, so that single requests may enable retries.
+	mws = append(mws, newRetrier(c.retry, c.retryOpts...))
+
+	if c.breaker != nil {
+		mws = append(mws, c.breaker)
+	}
+
+	if c.auth != nil {
+		mws = append(mws, c.auth)
+	}
+
+	mws = append(mws, c.middlewares...)
+
+	return append(mws, Telemetry())
 }
 
 func (c *client) Patch(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error) {
@@ -102,6 +214,13 @@ func (c *client) Get(ctx context.Context, request *Request, opts ...RequestOptio
 }
 
 func (c *client) processRequest(ctx context.Context, method string, request *Request, opts ...RequestOption) (rst Result, err error) {
+	return c.processRequestBody(ctx, method, request, nil, opts...)
+}
+
+// processRequestBody sends a buffered request whose body is read from body, or from request.Body when it is nil.
+func (c *client) processRequestBody(
+	ctx context.Context, method string, request *Request, body io.Reader, opts ...RequestOption,
+) (rst Result, err error) {
 	defer func() {
 		if err != nil && ctx.Err() != nil {
 			rst = Result{}
@@ -109,83 +228,223 @@ func (c *client) processRequest(ctx context.Context, method string, request *Req
 		}
 	}()
 
-	for _, opt := range opts {
-		opt(request)
+	request = request.with(opts...)
+
+	url, err := request.url()
+	if err != nil {
+		return Result{}, err
 	}
 
-	queryValues := URL.Values{}
+	ctx, span := c.onRequestStart(ctx, method, request.Path, url)
+	defer span.End()
 
-	for key, value := range request.QueryParams {
-		queryValues.Add(key, value)
+	if body == nil {
+		body = bytes.NewReader(request.Body)
 	}
 
-	uri := request.Host + request.Path
-	for p, v := range request.PathParams {
-		if strings.Contains(uri, ":"+p) {
-			uri = strings.ReplaceAll(uri, ":"+p, v)
-		}
+	rst, err = c.doRequest(ctx, url, request, method, body)
+	if err != nil {
+		c.onRequestError(span, err)
 	}
 
-	url, err := URL.Parse(uri)
+	return rst, err
+}
+
+func (c *client) doRequest(ctx context.Context, url string, request *Request, method string, requestBody io.Reader) (Result, error) {
+	res, err := c.send(ctx, url, request, method, requestBody, false)
 	if err != nil {
-		return Result{}, errors.New("error on parsing the request url")
+		return Result{}, err
 	}
 
-	url.RawQuery = queryValues.Encode()
+	defer func() { _ = res.Body.Close() }()
 
-	httpRequest, err := http.NewRequestWithContext(ctx, method, url.String(), bytes.NewBuffer(request.Body))
+	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return Result{}, err
 	}
 
+	result := Result{
+		Response:   body,
+		StatusCode: res.StatusCode,
+	}
+
+	if request.accepts(res.StatusCode) {
+		return result, nil
+	}
+
+	return result, c.statusCodeError(request, res.StatusCode, body)
+}
+
+// send builds the request and sends it through the middleware chain.
+func (c *client) send(
+	ctx context.Context, url string, request *Request, method string, body io.Reader, stream bool,
+) (*http.Response, error) {
+	ctx = withCall(ctx, &call{request: request, stream: stream})
+
+	httpRequest, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
 	for key, value := range request.Headers {
 		httpRequest.Header.Add(key, value)
 	}
 
-	ctx, span, start := c.onRequestStart(ctx, request.Host, request.Path, method)
-	defer span.End()
+	res, err := c.doer.Do(httpRequest)
+	if err != nil {
+		return nil, requestError(err).WithAttribute("http.request.body", c.redactor.Body(request.Body))
+	}
 
-	return c.doRequest(ctx, httpRequest, request, method, start, span)
+	return res, nil
 }
 
-func (c *client) doRequest(
-	ctx context.Context, httpRequest *http.Request, request *Request,
-	method string, start time.Time, span trace.Span,
-) (Result, error) {
-	res, err := c.http.Do(httpRequest)
-	if err != nil {
-		return Result{}, errors.ErrRequestError.WithCause(err).WithAttribute("http.request.body", string(request.Body))
+// statusCodeError maps a non acceptable response into a CustomError.
+func (c *client) statusCodeError(request *Request, status int, body []byte) error {
+	return classifyStatusCode(request, status, body).
+		WithAttrs(
+			errors.IntAttr("http.response.status_code", status),
+			errors.StringAttr("http.response.body", c.redactor.Body(body)),
+		)
+}
+
+// classifyStatusCode maps a non acceptable response status code into a CustomError, without its attributes.
+func classifyStatusCode(request *Request, status int, body []byte) errors.CustomError {
+	dErr := ErrUnexpectedStatusCode.WithKind(statusCodeToKind(status))
+	if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+		dErr = dErr.Retryable()
 	}
 
-	defer func() { _ = res.Body.Close() }()
+	if request.decodeAPIError {
+		if apiErr, ok := decodeAPIError(status, body); ok {
+			dErr = apiErr
+		}
+	}
 
-	body, err := io.ReadAll(res.Body)
+	return dErr
+}
+
+// errorRedactor redacts the URL held by the errors of the underlying Doer before they reach spans, logs and error
+// attributes. Its query is dropped, since it may carry credentials, like the API key set by [APIKeyQuery].
+type errorRedactor struct {
+	next     Doer
+	redactor *redact.Redactor
+}
+
+// Do sends the request, redacting the URL of its error.
+func (r *errorRedactor) Do(request *http.Request) (*http.Response, error) {
+	res, err := r.next.Do(request)
+
+	var urlErr *URL.Error
+	if err != nil && errors.As(err, &urlErr) {
+		urlErr.URL = r.redactURL(urlErr.URL)
+	}
+
+	return res, err
+}
+
+func (r *errorRedactor) redactURL(url string) string {
+	u, err := URL.Parse(url)
 	if err != nil {
-		return Result{}, err
+		return ""
 	}
 
-	c.onRequestEnd(ctx, request.Host, request.Path, method, res.StatusCode, start, span)
+	u.RawQuery, u.ForceQuery = "", false
 
-	result := Result{
-		Response:   body,
-		StatusCode: res.StatusCode,
+	return r.redactor.URL(u)
+}
+
+// timeouter bounds the requests sent through it with its timeout, or with the one of the request.
+// The timeout covers reading the response body, except for streamed requests, whose body is bounded by the caller.
+type timeouter struct {
+	next    Doer
+	timeout time.Duration
+	// attempt tells whether the timeouter bounds each attempt, rather than the whole call.
+	attempt bool
+}
+
+// Do sends the request, canceling it with ErrRequestTimeout once the timeout elapses.
+func (t *timeouter) Do(request *http.Request) (*http.Response, error) {
+	c := callFrom(request)
+
+	timeout, override := t.timeout, c.request.timeout
+	if t.attempt {
+		override = c.request.attemptTimeout
 	}
 
-	configAcceptable := request.acceptStatusCodes != nil && request.acceptStatusCodes[res.StatusCode]
-	defaultAcceptable := len(request.acceptStatusCodes) == 0 && res.StatusCode < http.StatusBadRequest
+	if override != nil {
+		timeout = *override
+	}
 
-	if configAcceptable || defaultAcceptable {
-		return result, nil
+	if timeout <= 0 {
+		return t.next.Do(request)
 	}
 
-	dErr := ErrUnexpectedStatusCode.WithKind(statusCodeToKind(res.StatusCode))
-	if res.StatusCode >= http.StatusInternalServerError {
-		dErr = dErr.Retryable()
+	ctx, cancel := context.WithCancelCause(request.Context())
+	timer := time.AfterFunc(timeout, func() { cancel(ErrRequestTimeout) })
+
+	res, err := t.next.Do(request.WithContext(ctx))
+	if err != nil {
+		timer.Stop()
+		defer cancel(nil)
+
+		if isTimeout(ctx) {
+			return nil, timeoutError(err, timeout)
+		}
+
+		return nil, err
+	}
+
+	if c.stream {
+		timer.Stop()
 	}
 
-	dErr = dErr.
-		WithAttribute("http.response.status_code", strconv.Itoa(res.StatusCode)).
-		WithAttribute("http.response.body", string(body))
+	res.Body = &timeoutBody{
+		ReadCloser: res.Body,
+		ctx:        ctx,
+		timeout:    timeout,
+		onClose: func() {
+			timer.Stop()
+			cancel(nil)
+		},
+	}
+
+	return res, nil
+}
+
+// timeoutBody maps the read failures caused by the timeout into ErrRequestTimeout,
+// releasing the timeout resources once the response body is closed.
+type timeoutBody struct {
+	io.ReadCloser
+
+	ctx     context.Context
+	timeout time.Duration
+	once    sync.Once
+	onClose func()
+}
+
+// Read reads the response body.
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && isTimeout(b.ctx) {
+		return n, timeoutError(err, b.timeout)
+	}
+
+	return n, err
+}
+
+// Close closes the response body.
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.onClose)
+
+	return err
+}
+
+// isTimeout reports whether ctx was canceled by the client timeout, rather than by the caller.
+func isTimeout(ctx context.Context) bool {
+	return ctx.Err() != nil && errors.Is(context.Cause(ctx), ErrRequestTimeout)
+}
 
-	return result, dErr
+func timeoutError(err error, timeout time.Duration) error {
+	return ErrRequestTimeout.WithCause(err).WithAttrs(errors.DurationAttr("http.client.timeout", timeout))
 }
@@ -0,0 +1,176 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/retry"
+)
+
+// serverHandler handles the nth request received by a test server, starting from 1.
+type serverHandler func(w http.ResponseWriter, r *http.Request, n int32)
+
+// newServer starts a test server answering requests with handler. It returns the server and the number of
+// requests it received.
+func newServer(t *testing.T, handler serverHandler) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, requests.Add(1))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// sequence answers each request with the next status code of statuses, repeating the last one.
+func sequence(statuses ...int) serverHandler {
+	return func(w http.ResponseWriter, r *http.Request, n int32) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		w.WriteHeader(statuses[min(int(n), len(statuses))-1])
+	}
+}
+
+// respond answers every request with the status code status currently holds.
+func respond(status *atomic.Int32) serverHandler {
+	return func(w http.ResponseWriter, _ *http.Request, _ int32) {
+		w.WriteHeader(int(status.Load()))
+	}
+}
+
+// slow delays the headers of the first slowRequests responses, and their bodies when slowBody is set.
+func slow(delay time.Duration, slowRequests int32, slowBody bool) serverHandler {
+	return func(w http.ResponseWriter, r *http.Request, n int32) {
+		delayed := n <= slowRequests
+
+		if delayed && slowBody {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+
+		if delayed {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tt := []struct {
+		desc         string
+		slowRequests int32
+		slowBody     bool
+		clientOpts   []httpclient.Option
+		requestOpts  []httpclient.RequestOption
+		timeout      bool
+		requests     int32
+	}{
+		{
+			desc:         "should time out waiting for the response",
+			slowRequests: 1,
+			clientOpts:   []httpclient.Option{httpclient.WithTimeout(50 * time.Millisecond)},
+			timeout:      true,
+			requests:     1,
+		},
+		{
+			desc:         "should time out reading the response body",
+			slowRequests: 1,
+			slowBody:     true,
+			clientOpts:   []httpclient.Option{httpclient.WithTimeout(50 * time.Millisecond)},
+			timeout:      true,
+			requests:     1,
+		},
+		{
+			desc:         "should respect the request timeout",
+			slowRequests: 1,
+			clientOpts:   []httpclient.Option{httpclient.WithTimeout(50 * time.Millisecond)},
+			requestOpts:  []httpclient.RequestOption{httpclient.WithRequestTimeout(time.Second)},
+			requests:     1,
+		},
+		{
+			desc:         "should not time out when the timeout is disabled",
+			slowRequests: 1,
+			clientOpts:   []httpclient.Option{httpclient.WithTimeout(0)},
+			requests:     1,
+		},
+		{
+			desc:         "should bound the retries of the request",
+			slowRequests: 3,
+			clientOpts: []httpclient.Option{
+				httpclient.WithTimeout(50 * time.Millisecond),
+				httpclient.WithRetry(fastRetry...),
+			},
+			timeout:  true,
+			requests: 1,
+		},
+		{
+			desc:         "should retry attempts that time out",
+			slowRequests: 1,
+			clientOpts: []httpclient.Option{
+				httpclient.WithAttemptTimeout(50 * time.Millisecond),
+				httpclient.WithRetry(fastRetry...),
+			},
+			requests: 2,
+		},
+		{
+			desc:         "should respect the request attempt timeout",
+			slowRequests: 1,
+			clientOpts: []httpclient.Option{
+				httpclient.WithAttemptTimeout(time.Second),
+				httpclient.WithRetry(fastRetry...),
+			},
+			requestOpts: []httpclient.RequestOption{httpclient.WithRequestAttemptTimeout(50 * time.Millisecond)},
+			requests:    2,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server, requests := newServer(t, slow(200*time.Millisecond, tc.slowRequests, tc.slowBody))
+
+			client := httpclient.New(tc.clientOpts...)
+
+			_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"}, tc.requestOpts...)
+
+			assert.Equal(t, tc.requests, requests.Load())
+			if tc.timeout {
+				assert.Equal(t, errors.Code(httpclient.ErrRequestTimeout), errors.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimeoutBoundsBackoff(t *testing.T) {
+	server, requests := newServer(t, sequence(http.StatusServiceUnavailable))
+
+	client := httpclient.New(
+		httpclient.WithTimeout(100*time.Millisecond),
+		httpclient.WithRetry(retry.WithInitialInterval(30*time.Millisecond), retry.WithMaxAttempts(0)),
+	)
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+
+	assert.Equal(t, errors.Code(httpclient.ErrRequestTimeout), errors.Code(err))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, requests.Load(), int32(10))
+}
@@ -7,6 +7,8 @@ type Client interface {
 	Put(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error)
 	Post(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error)
 	PostForm(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error)
+	PostMultipart(ctx context.Context, request *Request, form *MultipartForm, opts ...RequestOption) (rst Result, err error)
 	Delete(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error)
 	Get(ctx context.Context, request *Request, opts ...RequestOption) (rst Result, err error)
+	Stream(ctx context.Context, method string, request *Request, opts ...RequestOption) (rst *StreamResult, err error)
 }
@@ -5,3 +5,27 @@ import (
 )
 
 var ErrUnexpectedStatusCode = errors.New("unexpected status code").WithCode("UNEXPECTED_STATUS_CODE")
+
+// ErrCircuitOpen indicates that a request was not sent because the circuit breaker of its host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open").
+	WithCode("ERR_CIRCUIT_OPEN").
+	WithKind(errors.KindServiceUnavailable).
+	Retryable()
+
+// ErrRequestTimeout indicates that a request did not complete within the client timeout.
+// Unlike [errors.ErrContextCanceled], it is not caused by the caller canceling the request.
+var ErrRequestTimeout = errors.New("request timed out").
+	WithCode("ERR_REQUEST_TIMEOUT").
+	WithKind(errors.KindServiceUnavailable).
+	Retryable()
+
+// ErrFetchOAuth2Token indicates that an OAuth2 token could not be fetched to authenticate a request.
+var ErrFetchOAuth2Token = errors.New("could not fetch OAuth2 token").
+	WithCode("ERR_FETCH_OAUTH2_TOKEN").
+	WithKind(errors.KindUnauthenticated)
+
+// ErrLoadTLSCredentials indicates that the TLS certificates configured for the client transport could not be loaded.
+// [NewE] fails with it, while every request sent by a client created by [New] does.
+var ErrLoadTLSCredentials = errors.New("could not load TLS credentials").
+	WithCode("ERR_LOAD_TLS_CREDENTIALS").
+	WithKind(errors.KindInvalidInput)
@@ -0,0 +1,79 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+func TestErrorAttributesRedaction(t *testing.T) {
+	tt := []struct {
+		desc     string
+		opts     []httpclient.Option
+		body     string
+		expected string
+	}{
+		{
+			desc:     "should mask credentials by default",
+			body:     `{"user":"john","password":"123"}`,
+			expected: `{"password":"` + redact.DefaultMask + `","user":"john"}`,
+		},
+		{
+			desc:     "should mask form-urlencoded bodies",
+			body:     `user=john&token=abc`,
+			expected: `user=john&token=` + redact.DefaultMask,
+		},
+		{
+			desc: "should apply the configured redactor",
+			opts: []httpclient.Option{
+				httpclient.WithRedactor(redact.New(
+					redact.WithJSONPaths("user.document"),
+					redact.WithPatterns(regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)),
+				)),
+			},
+			body:     `{"user":{"document":"42","note":"ssn 123-45-6789"}}`,
+			expected: `{"user":{"document":"` + redact.DefaultMask + `","note":"ssn ` + redact.DefaultMask + `"}}`,
+		},
+		{
+			desc:     "should truncate large bodies",
+			opts:     []httpclient.Option{httpclient.WithRedactor(redact.New(redact.WithMaxBodySize(8)))},
+			body:     `not json at all`,
+			expected: `not json...[TRUNCATED]`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			_, err := httpclient.New(tc.opts...).Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+			require.Error(t, err)
+
+			assert.Equal(t, tc.expected, errors.Attributes(err)["http.response.body"])
+		})
+	}
+}
+
+func TestRequestBodyRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	server.Close()
+
+	_, err := httpclient.New().Post(context.Background(),
+		&httpclient.Request{Host: server.URL, Path: "/login", Body: []byte(`{"user":"john","password":"123"}`)})
+	require.Error(t, err)
+
+	assert.Equal(t, `{"password":"`+redact.DefaultMask+`","user":"john"}`, errors.Attributes(err)["http.request.body"])
+}
@@ -2,12 +2,12 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"os"
 
 	"github.com/lcnascimento/go-kit/http/httpclient"
 	"github.com/lcnascimento/go-kit/o11y/log"
+	"github.com/lcnascimento/go-kit/retry"
 )
 
 var (
@@ -20,7 +20,7 @@ func main() {
 
 	ctx := context.Background()
 
-	client := httpclient.New()
+	client := httpclient.New(httpclient.WithRetry(retry.WithMaxAttempts(5)))
 
 	req := &httpclient.Request{
 		Host: "https://api.open-meteo.com",
@@ -32,17 +32,11 @@ func main() {
 		},
 	}
 
-	res, err := client.Get(ctx, req, httpclient.WithAcceptStatusCode(http.StatusOK))
+	body, err := httpclient.GetJSON[map[string]any](ctx, client, req, httpclient.WithAcceptStatusCode(http.StatusOK))
 	if err != nil {
 		logger.Critical(ctx, err)
 		return
 	}
 
-	var body map[string]any
-	if err := json.Unmarshal(res.Response, &body); err != nil {
-		logger.Critical(ctx, err)
-		return
-	}
-
 	logger.Info(ctx, "temperature fetched", log.Any("data", body))
 }
@@ -0,0 +1,138 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+// GetJSON sends a GET request and decodes its JSON response body into Resp.
+func GetJSON[Resp any](ctx context.Context, c Client, request *Request, opts ...RequestOption) (Resp, error) {
+	acceptJSON(request)
+
+	rst, err := c.Get(ctx, request, opts...)
+
+	return decodeJSON[Resp](rst, err)
+}
+
+// DeleteJSON sends a DELETE request and decodes its JSON response body into Resp.
+func DeleteJSON[Resp any](ctx context.Context, c Client, request *Request, opts ...RequestOption) (Resp, error) {
+	acceptJSON(request)
+
+	rst, err := c.Delete(ctx, request, opts...)
+
+	return decodeJSON[Resp](rst, err)
+}
+
+// PostJSON encodes body as the JSON request body, sends a POST request and decodes its JSON response body into Resp.
+func PostJSON[Req, Resp any](
+	ctx context.Context, c Client, request *Request, body Req, opts ...RequestOption,
+) (Resp, error) {
+	if err := encodeJSON(request, body); err != nil {
+		var zero Resp
+		return zero, err
+	}
+
+	rst, err := c.Post(ctx, request, opts...)
+
+	return decodeJSON[Resp](rst, err)
+}
+
+// PutJSON encodes body as the JSON request body, sends a PUT request and decodes its JSON response body into Resp.
+func PutJSON[Req, Resp any](
+	ctx context.Context, c Client, request *Request, body Req, opts ...RequestOption,
+) (Resp, error) {
+	if err := encodeJSON(request, body); err != nil {
+		var zero Resp
+		return zero, err
+	}
+
+	rst, err := c.Put(ctx, request, opts...)
+
+	return decodeJSON[Resp](rst, err)
+}
+
+// PatchJSON encodes body as the JSON request body, sends a PATCH request and decodes its JSON response body into Resp.
+func PatchJSON[Req, Resp any](
+	ctx context.Context, c Client, request *Request, body Req, opts ...RequestOption,
+) (Resp, error) {
+	if err := encodeJSON(request, body); err != nil {
+		var zero Resp
+		return zero, err
+	}
+
+	rst, err := c.Patch(ctx, request, opts...)
+
+	return decodeJSON[Resp](rst, err)
+}
+
+func acceptJSON(request *Request) {
+	if request.Headers == nil {
+		request.Headers = make(map[string]string)
+	}
+
+	if _, ok := request.Headers["accept"]; !ok {
+		request.Headers["accept"] = contentType
+	}
+}
+
+func encodeJSON(request *Request, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return errors.ErrCastPayload.WithCause(err)
+	}
+
+	acceptJSON(request)
+	request.Body = payload
+
+	return nil
+}
+
+// decodeJSON decodes the response body into Resp. Empty bodies result in Resp zero value.
+func decodeJSON[Resp any](rst Result, err error) (Resp, error) {
+	var resp Resp
+
+	if err != nil {
+		return resp, err
+	}
+
+	if len(rst.Response) == 0 {
+		return resp, nil
+	}
+
+	if err := json.Unmarshal(rst.Response, &resp); err != nil {
+		return resp, errors.ErrCastPayload.
+			WithCause(err).
+			WithAttrs(errors.IntAttr("http.response.status_code", rst.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// decodeAPIError rebuilds the CustomError described by an [util.APIError] response body.
+// The downstream retryable flag takes precedence over the one inferred from the status code.
+func decodeAPIError(status int, body []byte) (errors.CustomError, bool) {
+	var apiErr util.APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code == "" {
+		return nil, false
+	}
+
+	dErr := errors.New("%s", apiErr.Message).
+		WithKind(statusCodeToKind(status)).
+		WithCode(errors.CodeType(apiErr.Code))
+
+	if apiErr.Retryable {
+		dErr = dErr.Retryable()
+	}
+
+	reasons, _ := apiErr.Details["reasons"].([]any)
+	for _, reason := range reasons {
+		if msg, ok := reason.(string); ok {
+			dErr = dErr.WithCause(errors.New("%s", msg))
+		}
+	}
+
+	return dErr, true
+}
@@ -0,0 +1,198 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// echoRequest answers with the method, headers and body of the request it received.
+func echoRequest(w http.ResponseWriter, r *http.Request, _ int32) {
+	body, _ := io.ReadAll(r.Body)
+
+	util.WriteResponse(w, http.StatusOK, map[string]any{
+		"method":       r.Method,
+		"content_type": r.Header.Get("Content-Type"),
+		"accept":       r.Header.Get("Accept"),
+		"body":         string(body),
+	})
+}
+
+type echo struct {
+	Method      string `json:"method"`
+	ContentType string `json:"content_type"`
+	Accept      string `json:"accept"`
+	Body        string `json:"body"`
+}
+
+func TestJSON(t *testing.T) {
+	server, _ := newServer(t, echoRequest)
+	client := httpclient.New()
+	ctx := context.Background()
+	body := user{ID: "1", Name: "john"}
+
+	tt := []struct {
+		desc   string
+		send   func(request *httpclient.Request) (echo, error)
+		method string
+		body   string
+	}{
+		{
+			desc: "should get json",
+			send: func(request *httpclient.Request) (echo, error) {
+				return httpclient.GetJSON[echo](ctx, client, request)
+			},
+			method: http.MethodGet,
+		},
+		{
+			desc: "should delete json",
+			send: func(request *httpclient.Request) (echo, error) {
+				return httpclient.DeleteJSON[echo](ctx, client, request)
+			},
+			method: http.MethodDelete,
+		},
+		{
+			desc: "should post json",
+			send: func(request *httpclient.Request) (echo, error) {
+				return httpclient.PostJSON[user, echo](ctx, client, request, body)
+			},
+			method: http.MethodPost,
+			body:   `{"id":"1","name":"john"}`,
+		},
+		{
+			desc: "should put json",
+			send: func(request *httpclient.Request) (echo, error) {
+				return httpclient.PutJSON[user, echo](ctx, client, request, body)
+			},
+			method: http.MethodPut,
+			body:   `{"id":"1","name":"john"}`,
+		},
+		{
+			desc: "should patch json",
+			send: func(request *httpclient.Request) (echo, error) {
+				return httpclient.PatchJSON[user, echo](ctx, client, request, body)
+			},
+			method: http.MethodPatch,
+			body:   `{"id":"1","name":"john"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.send(&httpclient.Request{Host: server.URL, Path: "/users"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.method, got.Method)
+			assert.Equal(t, "application/json", got.Accept)
+			assert.Equal(t, tc.body, got.Body)
+
+			if tc.body != "" {
+				assert.Equal(t, "application/json", got.ContentType)
+			}
+		})
+	}
+}
+
+func TestJSONDecoding(t *testing.T) {
+	tt := []struct {
+		desc     string
+		status   int
+		body     string
+		opts     []httpclient.RequestOption
+		expected *user
+		code     errors.CodeType
+		kind     errors.KindType
+	}{
+		{
+			desc:     "should decode the response body",
+			status:   http.StatusOK,
+			body:     `{"id":"1","name":"john"}`,
+			expected: &user{ID: "1", Name: "john"},
+		},
+		{
+			desc:   "should keep the zero value on empty bodies",
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "should fail on invalid bodies",
+			status: http.StatusOK,
+			body:   `{"id":`,
+			code:   errors.Code(errors.ErrCastPayload),
+		},
+		{
+			desc:   "should fail on non acceptable responses",
+			status: http.StatusNotFound,
+			body:   `{"code":"ERR_USER_NOT_FOUND","message":"user not found","retryable":false}`,
+			code:   errors.Code(httpclient.ErrUnexpectedStatusCode),
+			kind:   errors.KindNotFound,
+		},
+		{
+			desc:   "should decode api errors",
+			status: http.StatusNotFound,
+			body:   `{"code":"ERR_USER_NOT_FOUND","message":"user not found","retryable":false}`,
+			opts:   []httpclient.RequestOption{httpclient.WithAPIErrorDecoder()},
+			code:   "ERR_USER_NOT_FOUND",
+			kind:   errors.KindNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			got, err := httpclient.GetJSON[*user](context.Background(), httpclient.New(),
+				&httpclient.Request{Host: server.URL, Path: "/users/1"}, tc.opts...)
+
+			if tc.code != "" {
+				assert.Equal(t, tc.code, errors.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tc.kind != "" {
+				assert.Equal(t, tc.kind, errors.Kind(err))
+			}
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAPIErrorDecoderRoundTrip(t *testing.T) {
+	downstream := errors.New("payment declined").
+		WithCode("ERR_PAYMENT_DECLINED").
+		WithKind(errors.KindUnprocessable).
+		Retryable()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		util.WriteError(r.Context(), w, downstream)
+	}))
+	defer server.Close()
+
+	_, err := httpclient.PostJSON[json.RawMessage, json.RawMessage](context.Background(), httpclient.New(),
+		&httpclient.Request{Host: server.URL, Path: "/payments"}, json.RawMessage(`{}`), httpclient.WithAPIErrorDecoder())
+
+	assert.Equal(t, errors.Code(downstream), errors.Code(err))
+	assert.Equal(t, errors.KindUnprocessable, errors.Kind(err))
+	assert.True(t, errors.IsRetryable(err))
+	assert.Equal(t, "payment declined", err.Error())
+}
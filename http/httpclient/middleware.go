@@ -0,0 +1,269 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/retry"
+)
+
+// Doer sends an HTTP request and returns its response. It is implemented by [http.Client].
+type Doer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function into a Doer.
+type DoerFunc func(request *http.Request) (*http.Response, error)
+
+// Do calls f(request).
+func (f DoerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// Middleware wraps a Doer with cross-cutting behaviour, like caching, signing, request IDs or custom metrics.
+// See [WithMiddlewares] and [WithMiddlewareChain].
+//
+// Middlewares may send a request more than once, so they must not modify the request they receive. The ones
+// that need to change it must work on a copy, see [http.Request.Clone]. Non acceptable responses are returned
+// as regular responses, being mapped into errors by the client only after leaving the chain.
+type Middleware func(next Doer) Doer
+
+// chain wraps doer with the given middlewares, the first one being the outermost.
+// It also returns the Doers built by the middlewares that hold resources to be released when the client is closed.
+func chain(doer Doer, mws ...Middleware) (Doer, []io.Closer) {
+	var closers []io.Closer
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		doer = mws[i](doer)
+
+		if closer, ok := doer.(io.Closer); ok {
+			closers = append(closers, closer)
+		}
+	}
+
+	return doer, closers
+}
+
+// call holds the settings of the client call a request belongs to, shared with the built-in middlewares
+// through the request context.
+type call struct {
+	request *Request
+	stream  bool
+}
+
+type (
+	callKey    struct{}
+	attemptKey struct{}
+)
+
+func withCall(ctx context.Context, c *call) context.Context {
+	return context.WithValue(ctx, callKey{}, c)
+}
+
+// callFrom retrieves the call of the request, falling back to the default settings when there is none.
+func callFrom(request *http.Request) *call {
+	if c, ok := request.Context().Value(callKey{}).(*call); ok {
+		return c
+	}
+
+	return &call{
+		request: &Request{
+			Host: request.URL.Scheme + "://" + request.URL.Host,
+			Path: request.URL.Path,
+		},
+	}
+}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFrom retrieves the attempt number of the request, starting at 1.
+func attemptFrom(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+
+	return 1
+}
+
+// rewindable reports whether the request body can be read again, so that the request can be resent.
+func rewindable(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+}
+
+// rewind returns a copy of the request whose body is read from the beginning.
+func rewind(ctx context.Context, request *http.Request) (*http.Request, error) {
+	rewound := request.Clone(ctx)
+	if request.Body == nil || request.Body == http.NoBody {
+		return rewound, nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	rewound.Body = body
+
+	return rewound, nil
+}
+
+type telemetry struct {
+	next Doer
+}
+
+// Telemetry records the metrics of every request sent, sets the resend count and the status of the request span,
+// and propagates the trace context, the baggage and the correlation ID through the request headers.
+// It is the innermost middleware of the default chain, so that every attempt of a request is recorded.
+func Telemetry() Middleware {
+	return func(next Doer) Doer {
+		return &telemetry{next: next}
+	}
+}
+
+// Do sends the request, recording its telemetry.
+func (t *telemetry) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	c := callFrom(request)
+
+	request = request.Clone(ctx)
+	t.onRequestSend(ctx, request)
+
+	start := t.onAttemptStart(ctx, request, c.request.Path)
+
+	res, err := t.next.Do(request)
+	t.onRequestEnd(ctx, request, c.request.Path, res, err, start)
+
+	return res, err
+}
+
+type retrier struct {
+	next Doer
+
+	// idempotent enables retries of idempotent requests that do not configure retries by themselves.
+	idempotent bool
+	opts       []retry.Option
+}
+
+// Retry retries idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) on retryable errors, like transport
+// failures, 429 and 5xx responses. A Retry-After response header takes precedence over the exponential backoff.
+// See [retry.Do] for the default policy. Streamed requests, and the ones whose body can not be rewound, are never
+// retried, and neither are the ones rejected by an open circuit breaker, see [ErrCircuitOpen].
+// See [WithRequestRetry] and [WithoutRetry] to configure a single request.
+func Retry(opts ...retry.Option) Middleware {
+	return newRetrier(true, opts...)
+}
+
+func newRetrier(idempotent bool, opts ...retry.Option) Middleware {
+	return func(next Doer) Doer {
+		return &retrier{next: next, idempotent: idempotent, opts: opts}
+	}
+}
+
+// Do sends the request, resending it while it fails with retryable errors.
+// Non acceptable responses are buffered, so that they can be decoded into errors.
+func (r *retrier) Do(request *http.Request) (*http.Response, error) {
+	c := callFrom(request)
+	if !r.shouldRetry(request, c) {
+		return r.next.Do(request)
+	}
+
+	var (
+		attempt int
+		res     *http.Response
+		sendErr error
+	)
+
+	opts := append([]retry.Option{}, r.opts...)
+	opts = append(opts, c.request.retryOpts...)
+	opts = append(opts, retry.WithDelay(func(_ error, backoff time.Duration) time.Duration {
+		if res == nil {
+			return backoff
+		}
+
+		if retryAfter, ok := parseRetryAfter(res.Header); ok {
+			return retryAfter
+		}
+
+		return backoff
+	}))
+
+	// Open circuits fail fast, as resending the request would only hit the open circuit again.
+	shouldRetry := retry.ShouldRetry(opts...)
+	opts = append(opts, retry.WithRetryIf(func(err error) bool {
+		return !errors.Is(err, ErrCircuitOpen) && shouldRetry(err)
+	}))
+
+	err := retry.Do(request.Context(), func(ctx context.Context) error {
+		attempt++
+
+		res, sendErr = nil, nil
+
+		attemptRequest, err := rewind(withAttempt(ctx, attempt), request)
+		if err != nil {
+			sendErr = err
+			return err
+		}
+
+		res, sendErr = r.next.Do(attemptRequest)
+		if sendErr != nil {
+			return requestError(sendErr)
+		}
+
+		if c.request.accepts(res.StatusCode) {
+			return nil
+		}
+
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+
+		if err != nil {
+			res, sendErr = nil, err
+			return requestError(err)
+		}
+
+		res.Body = io.NopCloser(bytes.NewReader(body))
+
+		return classifyStatusCode(c.request, res.StatusCode, body)
+	}, opts...)
+
+	switch {
+	case res != nil && err != nil && isTimeout(request.Context()):
+		// the call timing out while waiting to retry fails it, rather than returning the response of the last attempt.
+		_ = res.Body.Close()
+		return nil, err
+	case res != nil:
+		return res, nil
+	case sendErr != nil:
+		return nil, sendErr
+	default:
+		return nil, err
+	}
+}
+
+func (r *retrier) shouldRetry(request *http.Request, c *call) bool {
+	if c.stream || !rewindable(request) {
+		return false
+	}
+
+	if c.request.retry != nil {
+		return *c.request.retry
+	}
+
+	return r.idempotent && idempotentMethods[request.Method]
+}
+
+// requestError maps a failure to send a request into a CustomError, keeping the ones raised by the client itself.
+func requestError(err error) errors.CustomError {
+	var cErr errors.CustomError
+	if errors.As(err, &cErr) {
+		return cErr
+	}
+
+	return errors.ErrRequestError.WithCause(err)
+}
@@ -0,0 +1,135 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+// recorder records the name of the middleware every time a request goes through it.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) middleware(name string) httpclient.Middleware {
+	return func(next httpclient.Doer) httpclient.Doer {
+		return httpclient.DoerFunc(func(request *http.Request) (*http.Response, error) {
+			r.mu.Lock()
+			r.calls = append(r.calls, name)
+			r.mu.Unlock()
+
+			request = request.Clone(request.Context())
+			request.Header.Add("X-Middlewares", name)
+
+			return next.Do(request)
+		})
+	}
+}
+
+func TestWithMiddlewares(t *testing.T) {
+	var received []http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header)
+
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	rec := &recorder{}
+	client := httpclient.New(
+		httpclient.WithRetry(fastRetry...),
+		httpclient.WithMiddlewares(rec.middleware("first")),
+		httpclient.WithMiddlewares(rec.middleware("second")),
+	)
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second", "first", "second"}, rec.calls, "should wrap every attempt in order")
+	require.Len(t, received, 2)
+	assert.Equal(t, []string{"first", "second"}, received[1].Values("X-Middlewares"), "should not leak headers across attempts")
+}
+
+func TestWithMiddlewareChain(t *testing.T) {
+	tt := []struct {
+		desc     string
+		chain    func(rec *recorder) []httpclient.Middleware
+		calls    []string
+		requests int32
+	}{
+		{
+			desc: "should replace the built-in middlewares",
+			chain: func(rec *recorder) []httpclient.Middleware {
+				return []httpclient.Middleware{rec.middleware("custom")}
+			},
+			calls:    []string{"custom"},
+			requests: 1,
+		},
+		{
+			desc: "should reorder the built-in middlewares",
+			chain: func(rec *recorder) []httpclient.Middleware {
+				return []httpclient.Middleware{rec.middleware("outer"), httpclient.Retry(fastRetry...), httpclient.Telemetry()}
+			},
+			calls:    []string{"outer"},
+			requests: 2,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server, requests := newServer(t, sequence(http.StatusServiceUnavailable, http.StatusOK))
+
+			rec := &recorder{}
+			client := httpclient.New(
+				httpclient.WithRetry(fastRetry...),
+				httpclient.WithMiddlewares(rec.middleware("ignored")),
+				httpclient.WithMiddlewareChain(tc.chain(rec)...),
+			)
+
+			_, _ = client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+
+			assert.Equal(t, tc.calls, rec.calls)
+			assert.Equal(t, tc.requests, requests.Load())
+		})
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	stub := func(status int, body string) httpclient.Middleware {
+		return func(httpclient.Doer) httpclient.Doer {
+			return httpclient.DoerFunc(func(request *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Request:    request,
+				}, nil
+			})
+		}
+	}
+
+	client := httpclient.New(httpclient.WithMiddlewares(stub(http.StatusOK, `{"id":"1"}`)))
+
+	res, err := client.Get(context.Background(), &httpclient.Request{Host: "http://unreachable.invalid", Path: "/"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1"}`, string(res.Response))
+
+	client = httpclient.New(httpclient.WithMiddlewares(stub(http.StatusNotFound, "")))
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: "http://unreachable.invalid", Path: "/"})
+	assert.Equal(t, errors.KindNotFound, errors.Kind(err), "should map non acceptable responses into errors")
+}
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// MultipartForm is a multipart/form-data request body, made of form fields and file parts.
+type MultipartForm struct {
+	Fields []FormField
+	Files  []FormFile
+}
+
+// FormField is a plain form field of a multipart request.
+type FormField struct {
+	Name  string
+	Value string
+}
+
+// FormFile is a file part of a multipart request. Its content is streamed, so it is never fully loaded in memory.
+// ContentType defaults to application/octet-stream.
+type FormFile struct {
+	Field       string
+	Filename    string
+	ContentType string
+	Content     io.Reader
+}
+
+// maxBufferedMultipartSize is the size up to which multipart forms are buffered, so that they can be resent.
+const maxBufferedMultipartSize = 1 << 20
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// PostMultipart sends a POST request with the given form as a multipart/form-data body, with its boundary and
+// content type set by the client. The response is buffered, following the same timeout, telemetry and error
+// mapping of the other buffered methods.
+//
+// Forms encoded in up to 1 MiB are buffered, so that they are retried and re-authenticated like any other request.
+// Larger forms are streamed while being encoded, so they are never fully loaded in memory. Since their body can not
+// be rewound, they are neither retried nor re-authenticated.
+func (c *client) PostMultipart(
+	ctx context.Context, request *Request, form *MultipartForm, opts ...RequestOption,
+) (rst Result, err error) {
+	if request.Headers == nil {
+		request.Headers = make(map[string]string)
+	}
+
+	reader, writer := io.Pipe()
+	mw := multipart.NewWriter(writer)
+
+	request.Headers["content-type"] = mw.FormDataContentType()
+
+	go func() {
+		writer.CloseWithError(writeMultipartForm(mw, form))
+	}()
+
+	// unblocks the form encoding when the request failed before consuming the whole body.
+	defer func() { _ = reader.Close() }()
+
+	buffered, err := io.ReadAll(io.LimitReader(reader, maxBufferedMultipartSize+1))
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(buffered) <= maxBufferedMultipartSize {
+		return c.processRequestBody(ctx, http.MethodPost, request, bytes.NewReader(buffered), opts...)
+	}
+
+	return c.processRequestBody(ctx, http.MethodPost, request, io.MultiReader(bytes.NewReader(buffered), reader), opts...)
+}
+
+func writeMultipartForm(mw *multipart.Writer, form *MultipartForm) error {
+	for _, field := range form.Fields {
+		if err := mw.WriteField(field.Name, field.Value); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range form.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="`+quoteEscaper.Replace(file.Field)+
+			`"; filename="`+quoteEscaper.Replace(file.Filename)+`"`)
+		header.Set("Content-Type", contentType)
+
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
@@ -0,0 +1,118 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+type multipartPart struct {
+	field       string
+	filename    string
+	contentType string
+	size        int
+}
+
+// multipartForm fails the first failures requests and records into parts those of the last multipart form received.
+func multipartForm(failures int32, parts *[]multipartPart) serverHandler {
+	return func(w http.ResponseWriter, r *http.Request, n int32) {
+		*parts = nil
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for part, err := reader.NextPart(); err == nil; part, err = reader.NextPart() {
+			content, _ := io.ReadAll(part)
+			*parts = append(*parts, multipartPart{
+				field:       part.FormName(),
+				filename:    part.FileName(),
+				contentType: part.Header.Get("Content-Type"),
+				size:        len(content),
+			})
+		}
+
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestPostMultipart(t *testing.T) {
+	var parts []multipartPart
+	server, _ := newServer(t, multipartForm(0, &parts))
+
+	form := &httpclient.MultipartForm{
+		Fields: []httpclient.FormField{{Name: "name", Value: "john"}},
+		Files: []httpclient.FormFile{
+			{Field: "avatar", Filename: "avatar.png", ContentType: "image/png", Content: strings.NewReader("png")},
+			{Field: "notes", Filename: `my "notes".txt`, Content: strings.NewReader("notes")},
+		},
+	}
+
+	_, err := httpclient.New().PostMultipart(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"}, form)
+	require.NoError(t, err)
+
+	assert.Equal(t, []multipartPart{
+		{field: "name", size: 4},
+		{field: "avatar", filename: "avatar.png", contentType: "image/png", size: 3},
+		{field: "notes", filename: `my "notes".txt`, contentType: "application/octet-stream", size: 5},
+	}, parts)
+}
+
+func TestPostMultipartRetry(t *testing.T) {
+	tt := []struct {
+		desc     string
+		size     int
+		requests int32
+		success  bool
+	}{
+		{
+			desc:     "should retry buffered forms",
+			size:     1024,
+			requests: 2,
+			success:  true,
+		},
+		{
+			desc:     "should not retry streamed forms",
+			size:     2 << 20,
+			requests: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var parts []multipartPart
+			server, requests := newServer(t, multipartForm(1, &parts))
+
+			form := &httpclient.MultipartForm{
+				Files: []httpclient.FormFile{{Field: "file", Filename: "file.bin", Content: bytes.NewReader(make([]byte, tc.size))}},
+			}
+
+			client := httpclient.New(httpclient.WithRetry(fastRetry...))
+
+			_, err := client.PostMultipart(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"}, form,
+				httpclient.WithRequestRetry())
+
+			assert.Equal(t, tc.requests, requests.Load())
+			require.Len(t, parts, 1)
+			assert.Equal(t, tc.size, parts[0].size)
+
+			if tc.success {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
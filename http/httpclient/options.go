@@ -4,39 +4,234 @@ import (
 	"crypto/tls"
 	"net/http"
 	"time"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/o11y/redact"
+	"github.com/lcnascimento/go-kit/retry"
 )
 
 type Option func(*client)
 
+// WithTimeout bounds each call made by the client, including its retries and reading its response body.
+// Defaults to 30 seconds. Calls that time out fail with [ErrRequestTimeout]. Zero disables the timeout.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *client) {
 		c.timeout = timeout
 	}
 }
 
+// WithAttemptTimeout bounds each attempt of the calls made by the client, so that a slow attempt is retried
+// while the call is still within [WithTimeout]. Attempts that time out fail with [ErrRequestTimeout], which is
+// retryable. Zero, the default, leaves the attempts bounded by the call timeout only.
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.attempt = timeout
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the client transport. The certificates given by
+// [WithCACertificate] and [WithClientCertificate], or by env vars, are added on top of it.
 func WithTLSConfig(cfg *tls.Config) Option {
 	return func(c *client) {
 		if cfg == nil {
 			return
 		}
 
+		c.transport.tls = cfg
+	}
+}
+
+// WithCACertificate sets the file holding the PEM encoded CA certificates used to verify servers.
+// Defaults to the HTTP_CLIENT_TLS_CA_FILE env var, or to the system pool.
+func WithCACertificate(caFile string) Option {
+	return func(c *client) {
+		c.transport.caFile = caFile
+	}
+}
+
+// WithClientCertificate sets the files holding the PEM encoded certificate and key sent for mutual TLS.
+// Defaults to the HTTP_CLIENT_TLS_CERT_FILE and HTTP_CLIENT_TLS_KEY_FILE env vars.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *client) {
+		c.transport.certFile = certFile
+		c.transport.keyFile = keyFile
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept across all hosts. Defaults to 100.
+// Zero means no limit.
+func WithMaxIdleConns(conns int) Option {
+	return func(c *client) {
+		c.transport.maxIdleConns = conns
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections kept for each host. Defaults to 2.
+func WithMaxIdleConnsPerHost(conns int) Option {
+	return func(c *client) {
+		c.transport.maxIdleConnsPerHost = conns
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before being closed. Defaults to 90 seconds.
+// Zero means no limit.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.transport.idleConnTimeout = timeout
+	}
+}
+
+// WithDialTimeout bounds the time to establish a new connection. Defaults to 30 seconds.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.transport.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout bounds the time to perform the TLS handshake of a new connection. Defaults to 10 seconds.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.transport.tlsHandshakeTimeout = timeout
+	}
+}
+
+// WithHTTP2 sets whether HTTP/2 is negotiated with the servers that support it. Defaults to true.
+func WithHTTP2(enabled bool) Option {
+	return func(c *client) {
+		c.transport.http2 = enabled
+	}
+}
+
+// WithProxyURL sends every request through the given proxy. A nil URL disables proxies.
+// Defaults to the proxy set by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars.
+func WithProxyURL(proxy *URL.URL) Option {
+	return func(c *client) {
+		c.transport.proxy = http.ProxyURL(proxy)
+	}
+}
+
+// WithTransport sets the transport requests are sent with, e.g. the recorder of the vcr package in tests.
+// The transport options, like [WithTLSConfig] and [WithMaxIdleConns], are ignored for custom transports.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *client) {
 		httpClient, ok := c.http.(*http.Client)
-		if !ok {
+		if !ok || transport == nil {
 			return
 		}
 
-		transport, ok := httpClient.Transport.(*http.Transport)
-		if !ok || transport == nil {
-			defaultTransport, ok := http.DefaultTransport.(*http.Transport)
-			if !ok {
-				return
-			}
+		httpClient.Transport = transport
+	}
+}
+
+// WithRetry enables retries of idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) on retryable errors,
+// like transport failures, 429 and 5xx responses. A Retry-After response header takes precedence over the
+// exponential backoff. See [retry.Do] for the default policy and [Retry] for the underlying middleware.
+func WithRetry(opts ...retry.Option) Option {
+	return func(c *client) {
+		c.retry = true
+		c.retryOpts = append(c.retryOpts, opts...)
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker for each host the client sends requests to.
+// While the circuit of a host is open, its requests fail fast with [ErrCircuitOpen]. See [CircuitBreaker].
+func WithCircuitBreaker(opts ...CircuitBreakerOption) Option {
+	return func(c *client) {
+		c.breaker = CircuitBreaker(opts...)
+	}
+}
+
+// WithAuthenticator authenticates every request sent by the client with the given Authenticator.
+// See [BearerToken], [BasicAuth], [APIKeyHeader], [APIKeyQuery] and [OAuth2ClientCredentials].
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *client) {
+		c.auth = Authentication(auth)
+	}
+}
 
-			transport = defaultTransport.Clone()
+// WithCache caches the responses of GET requests in the given store, e.g. [NewMemoryCache].
+// The cache is the outermost middleware, so fresh cached responses skip retries and circuit breakers. See [Cache].
+func WithCache(store CacheStore) Option {
+	return func(c *client) {
+		c.cache = Cache(store)
+	}
+}
+
+// WithMiddlewares adds the given middlewares to the client chain, e.g. for signing, request IDs or custom metrics.
+// They wrap every request in the given order, after the cache, retry, circuit breaker and authentication
+// middlewares, and before the telemetry one. See [WithMiddlewareChain] to reorder or replace the built-in middlewares.
+func WithMiddlewares(mws ...Middleware) Option {
+	return func(c *client) {
+		c.middlewares = append(c.middlewares, mws...)
+	}
+}
+
+// WithMiddlewareChain replaces the whole client chain, built-in middlewares included, with the given middlewares.
+// The first middleware is the outermost one. The built-in middlewares are [Cache], [Retry], [CircuitBreaker],
+// [Authentication] and [Telemetry], which may be reordered, replaced or left out.
+//
+// The options that configure the built-in middlewares, like [WithCache], [WithRetry], [WithCircuitBreaker],
+// [WithAuthenticator] and [WithMiddlewares], are ignored, and [WithRequestRetry] only takes effect when
+// the chain holds a [Retry].
+func WithMiddlewareChain(mws ...Middleware) Option {
+	return func(c *client) {
+		c.chain = append([]Middleware{}, mws...)
+	}
+}
+
+// WithRedactor sets the redactor applied to the request and response bodies attached to errors.
+// Defaults to [redact.Default], which masks well known credentials and truncates large bodies.
+func WithRedactor(redactor *redact.Redactor) Option {
+	return func(c *client) {
+		if redactor == nil {
+			return
 		}
 
-		transport.TLSClientConfig = cfg
-		httpClient.Transport = transport
+		c.redactor = redactor
+	}
+}
+
+type CircuitBreakerOption func(*breakerConfig)
+
+// WithBreakerConsecutiveFailures opens the circuit after the given number of consecutive failures.
+// Defaults to 5. Zero disables this threshold.
+func WithBreakerConsecutiveFailures(failures int) CircuitBreakerOption {
+	return func(c *breakerConfig) {
+		c.consecutiveFailures = failures
+	}
+}
+
+// WithBreakerFailureRatio opens the circuit when the ratio of failed requests within the window reaches
+// the given ratio, as long as at least minRequests were sent. Defaults to 0.5 and 10 requests.
+// A zero ratio disables this threshold.
+func WithBreakerFailureRatio(ratio float64, minRequests int) CircuitBreakerOption {
+	return func(c *breakerConfig) {
+		c.failureRatio = ratio
+		c.minRequests = minRequests
+	}
+}
+
+// WithBreakerWindow sets the period after which the counts of a closed circuit are cleared. Defaults to 1 minute.
+func WithBreakerWindow(window time.Duration) CircuitBreakerOption {
+	return func(c *breakerConfig) {
+		c.window = window
+	}
+}
+
+// WithBreakerCoolDown sets how long a circuit stays open before letting trial requests through.
+// Defaults to 30 seconds.
+func WithBreakerCoolDown(coolDown time.Duration) CircuitBreakerOption {
+	return func(c *breakerConfig) {
+		c.coolDown = coolDown
+	}
+}
+
+// WithBreakerHalfOpenRequests sets the number of concurrent trial requests allowed by a half-open circuit.
+// Defaults to 1.
+func WithBreakerHalfOpenRequests(requests int) CircuitBreakerOption {
+	return func(c *breakerConfig) {
+		c.halfOpenRequests = requests
 	}
 }
 
@@ -51,3 +246,48 @@ func WithAcceptStatusCode(code int) RequestOption {
 		r.acceptStatusCodes[code] = true
 	}
 }
+
+// WithRequestRetry enables retries for a single request, regardless of its method.
+// The given options are applied on top of the ones given to [WithRetry].
+func WithRequestRetry(opts ...retry.Option) RequestOption {
+	return func(r *Request) {
+		enabled := true
+
+		r.retry = &enabled
+		r.retryOpts = append(r.retryOpts, opts...)
+	}
+}
+
+// WithoutRetry disables retries for a single request.
+func WithoutRetry() RequestOption {
+	return func(r *Request) {
+		enabled := false
+
+		r.retry = &enabled
+	}
+}
+
+// WithRequestTimeout overrides the client timeout for a single request. Zero disables the timeout.
+// When retries are enabled, the timeout bounds all the attempts together.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(r *Request) {
+		r.timeout = &timeout
+	}
+}
+
+// WithRequestAttemptTimeout overrides the client attempt timeout, see [WithAttemptTimeout], for a single request.
+// Zero disables the attempt timeout.
+func WithRequestAttemptTimeout(timeout time.Duration) RequestOption {
+	return func(r *Request) {
+		r.attemptTimeout = &timeout
+	}
+}
+
+// WithAPIErrorDecoder decodes error responses holding the httpserver util.APIError payload, as written by httpserver,
+// back into a CustomError that keeps the downstream code, retryable flag and reasons.
+// Responses that do not hold such payload fail with [ErrUnexpectedStatusCode], as usual.
+func WithAPIErrorDecoder() RequestOption {
+	return func(r *Request) {
+		r.decodeAPIError = true
+	}
+}
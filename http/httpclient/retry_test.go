@@ -0,0 +1,171 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/retry"
+)
+
+var fastRetry = []retry.Option{
+	retry.WithInitialInterval(time.Millisecond),
+	retry.WithMaxInterval(time.Millisecond),
+	retry.WithMaxAttempts(3),
+}
+
+func TestRetry(t *testing.T) {
+	tt := []struct {
+		desc     string
+		method   string
+		statuses []int
+		opts     []httpclient.RequestOption
+		kind     errors.KindType
+		requests int32
+	}{
+		{
+			desc:     "should retry idempotent requests until success",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			requests: 3,
+		},
+		{
+			desc:     "should retry rate limited requests",
+			method:   http.MethodPut,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			requests: 2,
+		},
+		{
+			desc:     "should stop when max attempts is reached",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable},
+			kind:     errors.KindServiceUnavailable,
+			requests: 3,
+		},
+		{
+			desc:     "should not retry non retryable responses",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusNotFound, http.StatusOK},
+			kind:     errors.KindNotFound,
+			requests: 1,
+		},
+		{
+			desc:     "should not retry non idempotent requests",
+			method:   http.MethodPost,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			kind:     errors.KindServiceUnavailable,
+			requests: 1,
+		},
+		{
+			desc:     "should retry non idempotent requests enabling retries",
+			method:   http.MethodPost,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			opts:     []httpclient.RequestOption{httpclient.WithRequestRetry()},
+			requests: 2,
+		},
+		{
+			desc:     "should not retry requests disabling retries",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			opts:     []httpclient.RequestOption{httpclient.WithoutRetry()},
+			kind:     errors.KindServiceUnavailable,
+			requests: 1,
+		},
+		{
+			desc:     "should respect accepted status codes",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			opts:     []httpclient.RequestOption{httpclient.WithAcceptStatusCode(http.StatusServiceUnavailable)},
+			requests: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server, requests := newServer(t, sequence(tc.statuses...))
+
+			client := httpclient.New(httpclient.WithRetry(fastRetry...))
+			request := &httpclient.Request{Host: server.URL, Path: "/", Body: []byte(`{"name":"john"}`)}
+
+			var err error
+
+			switch tc.method {
+			case http.MethodGet:
+				_, err = client.Get(context.Background(), request, tc.opts...)
+			case http.MethodPut:
+				_, err = client.Put(context.Background(), request, tc.opts...)
+			case http.MethodPost:
+				_, err = client.Post(context.Background(), request, tc.opts...)
+			}
+
+			assert.Equal(t, tc.requests, requests.Load())
+			if tc.kind == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, errors.Code(httpclient.ErrUnexpectedStatusCode), errors.Code(err))
+				assert.Equal(t, tc.kind, errors.Kind(err))
+			}
+		})
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.WithRetry(fastRetry...))
+
+	_, err := client.Put(context.Background(), &httpclient.Request{Host: server.URL, Path: "/", Body: []byte(`{"id":1}`)})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"id":1}`, `{"id":1}`}, bodies)
+}
+
+func TestRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.WithRetry(fastRetry...))
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"},
+		httpclient.WithRequestRetry(retry.WithMaxAttempts(2)))
+
+	assert.Equal(t, errors.KindResourceExhausted, errors.Kind(err))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetryReusedRequest(t *testing.T) {
+	server, requests := newServer(t, sequence(http.StatusServiceUnavailable))
+
+	client := httpclient.New(httpclient.WithRetry(fastRetry...))
+	request := &httpclient.Request{Host: server.URL, Path: "/"}
+
+	_, err := client.Post(context.Background(), request, httpclient.WithRequestRetry(retry.WithMaxAttempts(2)))
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	_, err = client.Post(context.Background(), request)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load(), "should not keep the options of previous calls")
+}
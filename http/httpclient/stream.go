@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+// maxStreamErrorBodySize limits how much of a non acceptable streamed response is read into its error.
+const maxStreamErrorBodySize = 64 * 1024
+
+// Stream sends a request whose body is streamed from request.BodyReader, or from request.Body when it is nil,
+// and returns the response without buffering its body, e.g. for large downloads or server-sent events.
+//
+// Status code acceptance rules and error mapping are the same as the buffered methods. Streamed requests are
+// never retried, since their body can not be rewound. The client timeout bounds the time to receive the
+// response headers only, while reading the body is bounded by ctx.
+func (c *client) Stream(
+	ctx context.Context, method string, request *Request, opts ...RequestOption,
+) (rst *StreamResult, err error) {
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			rst = nil
+			err = errors.ErrContextCanceled
+		}
+	}()
+
+	request = request.with(opts...)
+
+	url, err := request.url()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := c.onRequestStart(ctx, method, request.Path, url)
+
+	rst, err = c.sendStream(ctx, url, request, method, span)
+	if err != nil {
+		c.onRequestError(span, err)
+		span.End()
+
+		return nil, err
+	}
+
+	return rst, nil
+}
+
+func (c *client) sendStream(
+	ctx context.Context, url string, request *Request, method string, span trace.Span,
+) (*StreamResult, error) {
+	body := request.BodyReader
+	if body == nil {
+		body = bytes.NewReader(request.Body)
+	}
+
+	res, err := c.send(ctx, url, request, method, body, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.accepts(res.StatusCode) {
+		defer func() { _ = res.Body.Close() }()
+
+		errBody, _ := io.ReadAll(io.LimitReader(res.Body, maxStreamErrorBodySize))
+
+		return nil, c.statusCodeError(request, res.StatusCode, errBody)
+	}
+
+	return &StreamResult{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body: &streamBody{
+			ReadCloser: res.Body,
+			onClose:    func() { span.End() },
+		},
+	}, nil
+}
+
+// streamBody ends the request span once the response body is closed.
+type streamBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	onClose func()
+}
+
+// Close closes the response body.
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.onClose)
+
+	return err
+}
@@ -0,0 +1,131 @@
+package httpclient_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+func TestStream(t *testing.T) {
+	tt := []struct {
+		desc     string
+		request  *httpclient.Request
+		status   int
+		opts     []httpclient.RequestOption
+		expected string
+		kind     errors.KindType
+	}{
+		{
+			desc:     "should stream the request body reader",
+			request:  &httpclient.Request{BodyReader: strings.NewReader("streamed")},
+			status:   http.StatusOK,
+			expected: "streamed",
+		},
+		{
+			desc:     "should fall back to the request body",
+			request:  &httpclient.Request{Body: []byte("buffered")},
+			status:   http.StatusOK,
+			expected: "buffered",
+		},
+		{
+			desc:    "should map non acceptable responses",
+			request: &httpclient.Request{BodyReader: strings.NewReader("streamed")},
+			status:  http.StatusConflict,
+			kind:    errors.KindConflict,
+		},
+		{
+			desc:     "should respect accepted status codes",
+			request:  &httpclient.Request{BodyReader: strings.NewReader("streamed")},
+			status:   http.StatusConflict,
+			opts:     []httpclient.RequestOption{httpclient.WithAcceptStatusCode(http.StatusConflict)},
+			expected: "streamed",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Method", r.Method)
+				w.WriteHeader(tc.status)
+				_, _ = io.Copy(w, r.Body)
+			}))
+			defer server.Close()
+
+			tc.request.Host = server.URL
+			tc.request.Path = "/files"
+
+			res, err := httpclient.New().Stream(context.Background(), http.MethodPut, tc.request, tc.opts...)
+
+			if tc.kind != "" {
+				assert.Equal(t, errors.Code(httpclient.ErrUnexpectedStatusCode), errors.Code(err))
+				assert.Equal(t, tc.kind, errors.Kind(err))
+				assert.Nil(t, res)
+
+				return
+			}
+
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, res.Body.Close()) }()
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, res.StatusCode)
+			assert.Equal(t, http.MethodPut, res.Header.Get("X-Method"))
+			assert.Equal(t, tc.expected, string(body))
+		})
+	}
+}
+
+func TestStreamReadsBeforeTheBodyEnds(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := httpclient.New(httpclient.WithTimeout(50 * time.Millisecond))
+
+	res, err := client.Stream(context.Background(), http.MethodGet, &httpclient.Request{Host: server.URL, Path: "/events"})
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// the client timeout bounds the response headers only
+	time.Sleep(100 * time.Millisecond)
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	require.NoError(t, err)
+
+	assert.Equal(t, "data: first\n", line)
+}
+
+func TestStreamIsNotRetried(t *testing.T) {
+	server, requests := newServer(t, sequence(http.StatusServiceUnavailable, http.StatusOK))
+
+	client := httpclient.New(httpclient.WithRetry(fastRetry...))
+
+	_, err := client.Stream(context.Background(), http.MethodGet, &httpclient.Request{Host: server.URL, Path: "/"})
+
+	assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err))
+	assert.Equal(t, int32(1), requests.Load())
+}
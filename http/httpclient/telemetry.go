@@ -4,19 +4,31 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/semconv/v1.37.0/httpconv"
 	"go.opentelemetry.io/otel/trace"
 
+	URL "net/url"
+
+	oMetric "go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/o11y/baggage"
+	"github.com/lcnascimento/go-kit/o11y/log"
 	"github.com/lcnascimento/go-kit/o11y/metric"
+
+	o11yTrace "github.com/lcnascimento/go-kit/o11y/trace"
 )
 
+const correlationIDHeader = "X-Correlation-Key"
+
 var (
 	pkg = "github.com/lcnascimento/go-kit/http/httpclient"
 
@@ -25,46 +37,224 @@ var (
 
 	activeRequestsMetric, _  = httpconv.NewClientActiveRequests(meter)
 	requestDurationMetric, _ = httpconv.NewClientRequestDuration(meter)
+	openConnectionsMetric, _ = httpconv.NewClientOpenConnections(meter)
+	connDurationMetric, _    = httpconv.NewClientConnectionDuration(meter)
 	requestsCounter          = metric.MustIntCounter(meter, "http.client.request.total", "Number of HTTP requests")
+	cacheRequestsCounter     = metric.MustIntCounter(
+		meter,
+		"http.client.cache.request.total",
+		"Number of HTTP requests looked up in the response cache",
+	)
+	circuitStateGauge = metric.MustFloat64ObservableGauge(
+		meter,
+		"http.client.circuit_breaker.state",
+		"State of the circuit breaker of each host: 0 for closed, 1 for half-open and 2 for open",
+	)
+
+	logger = log.MustNewLogger(pkg)
 )
 
-func (c *client) onRequestStart(ctx context.Context, host, path, method string) (context.Context, trace.Span, time.Time) {
+func (c *client) onRequestStart(ctx context.Context, method, route, url string) (context.Context, trace.Span) {
 	attrs := []attribute.KeyValue{
-		attribute.String(string(semconv.NetworkPeerAddressKey), host),
-		attribute.String(string(semconv.HTTPRequestMethodKey), method),
-		attribute.String(string(semconv.HTTPRouteKey), path),
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.HTTPRoute(route),
 	}
 
-	operation := fmt.Sprintf("%s %s", method, path)
-	ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(attrs...))
+	if u, err := URL.Parse(url); err == nil {
+		host, port := serverAddress(u)
 
-	const port = 80
+		attrs = append(attrs,
+			semconv.ServerAddress(host),
+			semconv.ServerPort(port),
+			semconv.URLScheme(u.Scheme),
+			semconv.URLFull(c.redactor.URL(u)),
+		)
+	}
 
-	activeRequestsMetric.Add(ctx, 1, host, port, attrs...)
+	operation := fmt.Sprintf("%s %s", method, route)
+	return tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func (t *telemetry) onAttemptStart(ctx context.Context, request *http.Request, route string) time.Time {
+	host, port := serverAddress(request.URL)
 
-	return ctx, span, time.Now()
+	activeRequestsMetric.Add(ctx, 1, host, port, metricAttrs(request, route)...)
+
+	return time.Now()
 }
 
-func (c *client) onRequestEnd(ctx context.Context, host, path, method string, status int, start time.Time, span trace.Span) {
-	latency := time.Since(start).Milliseconds()
+// onRequestEnd records the metrics of an attempt, either when its response is received or when it fails to be sent.
+func (t *telemetry) onRequestEnd(
+	ctx context.Context, request *http.Request, route string, res *http.Response, err error, start time.Time,
+) {
+	duration := time.Since(start).Seconds()
+	span := trace.SpanFromContext(ctx)
+	host, port := serverAddress(request.URL)
+	attrs := metricAttrs(request, route)
 
-	attrs := []attribute.KeyValue{
-		attribute.String(string(semconv.NetworkPeerAddressKey), host),
-		attribute.String(string(semconv.HTTPRequestMethodKey), method),
-		attribute.String(string(semconv.HTTPRouteKey), path),
+	activeRequestsMetric.Add(ctx, -1, host, port, attrs...)
+
+	if attempt := attemptFrom(ctx); attempt > 1 {
+		resendCount := semconv.HTTPRequestResendCount(attempt - 1)
+
+		attrs = append(attrs, resendCount)
+		span.SetAttributes(resendCount)
 	}
 
-	const port = 80
+	switch {
+	case err != nil:
+		errType := errorType(err)
 
-	activeRequestsMetric.Add(ctx, -1, host, port, attrs...)
+		attrs = append(attrs, errType)
+		span.SetAttributes(errType)
+		span.SetStatus(codes.Error, errType.Value.AsString())
+	case res.StatusCode >= http.StatusBadRequest:
+		status := semconv.HTTPResponseStatusCode(res.StatusCode)
+		errType := semconv.ErrorTypeKey.String(strconv.Itoa(res.StatusCode))
 
-	attrs = append(attrs, attribute.Int(string(semconv.HTTPResponseStatusCodeKey), status))
-	requestsCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
-	requestDurationMetric.Record(ctx, float64(latency), httpconv.RequestMethodAttr(method), host, port, attrs...)
+		attrs = append(attrs, status, errType)
+		span.SetAttributes(status, errType)
+		span.SetStatus(codes.Error, errType.Value.AsString())
+	default:
+		status := semconv.HTTPResponseStatusCode(res.StatusCode)
 
-	if status >= http.StatusInternalServerError {
-		span.SetStatus(codes.Error, semconv.HTTPResponseStatusCode(status).Value.AsString())
-	} else {
+		attrs = append(attrs, status)
+		span.SetAttributes(status)
 		span.SetStatus(codes.Unset, "")
 	}
+
+	counterAttrs := append([]attribute.KeyValue{semconv.ServerAddress(host), semconv.ServerPort(port)}, attrs...)
+
+	requestsCounter.Add(ctx, 1, metric.WithAttributes(counterAttrs...))
+	requestDurationMetric.Record(ctx, duration, httpconv.RequestMethodAttr(request.Method), host, port, attrs...)
+}
+
+// onRequestSend propagates the trace context, the baggage and the correlation ID through the request headers.
+func (t *telemetry) onRequestSend(ctx context.Context, request *http.Request) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	if cID := baggage.CorrelationID(ctx); cID != "" && request.Header.Get(correlationIDHeader) == "" {
+		request.Header.Set(correlationIDHeader, cID)
+	}
+}
+
+func (c *client) onRequestError(span trace.Span, err error) {
+	o11yTrace.RecordError(span, err)
+}
+
+func (c *pooledConn) onStateChange(from, to httpconv.ConnectionStateAttr) {
+	ctx := context.Background()
+
+	if from != "" {
+		openConnectionsMetric.AddSet(ctx, -1, c.metricAttrs(from))
+	}
+
+	openConnectionsMetric.AddSet(ctx, 1, c.metricAttrs(to))
+}
+
+func (c *pooledConn) onClose() {
+	ctx := context.Background()
+
+	if c.state != "" {
+		openConnectionsMetric.AddSet(ctx, -1, c.metricAttrs(c.state))
+	}
+
+	connDurationMetric.RecordSet(ctx, time.Since(c.openedAt).Seconds(), c.metricAttrs(""))
+}
+
+// metricAttrs returns the attributes of the connection metrics. They are built by hand, since the httpconv
+// instruments drop their required attributes when no optional one is given.
+func (c *pooledConn) metricAttrs(state httpconv.ConnectionStateAttr) attribute.Set {
+	attrs := []attribute.KeyValue{semconv.ServerAddress(c.host), semconv.ServerPort(c.port)}
+	if state != "" {
+		attrs = append(attrs, semconv.HTTPConnectionStateKey.String(string(state)))
+	}
+
+	return attribute.NewSet(attrs...)
+}
+
+func (cb *circuitBreaker) onCreate() oMetric.Registration {
+	registration, _ := meter.RegisterCallback(func(_ context.Context, o oMetric.Observer) error {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+
+		for host, circuit := range cb.hosts {
+			attr := attribute.String(string(semconv.ServerAddressKey), host)
+			o.ObserveFloat64(circuitStateGauge, float64(circuit.state), oMetric.WithAttributes(attr))
+		}
+
+		return nil
+	}, circuitStateGauge)
+
+	return registration
+}
+
+func (c *client) onTransportError(err error) {
+	logger.Error(context.Background(), err)
+}
+
+func (cb *circuitBreaker) onStateChange(ctx context.Context, host string, from, to breakerState) {
+	attrs := []log.Attr{
+		log.String(string(semconv.ServerAddressKey), host),
+		log.String("circuit_breaker.previous_state", from.String()),
+		log.String("circuit_breaker.state", to.String()),
+	}
+
+	if to == breakerOpen {
+		logger.Warn(ctx, "circuit breaker opened", attrs...)
+		return
+	}
+
+	logger.Info(ctx, fmt.Sprintf("circuit breaker %s", to), attrs...)
+}
+
+func (c *cache) onLookup(ctx context.Context, request *http.Request, status cacheStatus) {
+	attr := attribute.String("http.client.cache.status", string(status))
+	trace.SpanFromContext(ctx).SetAttributes(attr)
+
+	host, port := serverAddress(request.URL)
+
+	attrs := append(metricAttrs(request, callFrom(request).request.Path),
+		semconv.ServerAddress(host),
+		semconv.ServerPort(port),
+		attr,
+	)
+
+	cacheRequestsCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// metricAttrs returns the attributes shared by the request metrics, besides the server address and port.
+func metricAttrs(request *http.Request, route string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(request.Method),
+		semconv.URLScheme(request.URL.Scheme),
+		semconv.HTTPRoute(route),
+	}
+}
+
+// serverAddress returns the host and port of the given URL, defaulting the port by its scheme.
+func serverAddress(u *URL.URL) (string, int) {
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		port = 80
+		if u.Scheme == "https" {
+			port = 443
+		}
+	}
+
+	return u.Hostname(), port
+}
+
+// errorType identifies a transport failure by its code, when raised by the client, or by the type of its cause.
+func errorType(err error) attribute.KeyValue {
+	if code := errors.Code(err); code != errors.CodeUnknown {
+		return semconv.ErrorTypeKey.String(string(code))
+	}
+
+	var urlErr *URL.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	return semconv.ErrorType(err)
 }
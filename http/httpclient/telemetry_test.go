@@ -0,0 +1,234 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	URL "net/url"
+
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/o11y/baggage"
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+var (
+	spans   = tracetest.NewSpanRecorder()
+	metrics = sdkMetric.NewManualReader()
+)
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(spans)))
+	otel.SetMeterProvider(sdkMetric.NewMeterProvider(sdkMetric.WithReader(metrics)))
+
+	os.Exit(m.Run())
+}
+
+// endedSpan returns the attributes of the ended span sent to the given port.
+func endedSpan(t *testing.T, port int) map[attribute.Key]attribute.Value {
+	t.Helper()
+
+	for _, span := range spans.Ended() {
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, attr := range span.Attributes() {
+			attrs[attr.Key] = attr.Value
+		}
+
+		if attrs["server.port"].AsInt64() == int64(port) {
+			return attrs
+		}
+	}
+
+	require.Failf(t, "span not found", "no span ended for port %d", port)
+
+	return nil
+}
+
+// dataPoints returns the attributes and values of the data points of the named metric recorded for the given port.
+func dataPoints(t *testing.T, name string, port int) map[attribute.Set]float64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(context.Background(), &rm))
+
+	points := make(map[attribute.Set]float64)
+	add := func(attrs attribute.Set, value float64) {
+		if v, ok := attrs.Value("server.port"); ok && v.AsInt64() == int64(port) {
+			points[attrs] = value
+		}
+	}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, float64(dp.Value))
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					add(dp.Attributes, dp.Sum)
+				}
+			}
+		}
+	}
+
+	return points
+}
+
+func serverPort(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+
+	u, err := URL.Parse(server.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	return port
+}
+
+func TestRequestTelemetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	port := serverPort(t, server)
+
+	_, err := httpclient.New().Get(context.Background(), &httpclient.Request{
+		Host:        server.URL,
+		Path:        "/users/:id",
+		PathParams:  httpclient.PathParams{"id": "1"},
+		QueryParams: httpclient.QueryParams{"access_token": "secret"},
+	})
+	require.Error(t, err)
+
+	span := endedSpan(t, port)
+
+	assert.Equal(t, "GET", span["http.request.method"].AsString())
+	assert.Equal(t, "/users/:id", span["http.route"].AsString())
+	assert.Equal(t, "127.0.0.1", span["server.address"].AsString())
+	assert.Equal(t, "http", span["url.scheme"].AsString())
+	assert.Equal(t, server.URL+"/users/1?access_token="+URL.QueryEscape(redact.DefaultMask), span["url.full"].AsString())
+	assert.Equal(t, int64(http.StatusNotFound), span["http.response.status_code"].AsInt64())
+	assert.Equal(t, "404", span["error.type"].AsString())
+
+	durations := dataPoints(t, "http.client.request.duration", port)
+	require.Len(t, durations, 1)
+
+	for attrs, seconds := range durations {
+		status, _ := attrs.Value("http.response.status_code")
+		assert.Equal(t, int64(http.StatusNotFound), status.AsInt64())
+		assert.Less(t, seconds, 1.0, "should record durations in seconds")
+	}
+}
+
+func TestTransportFailureTelemetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	server.Close()
+
+	port := serverPort(t, server)
+
+	_, err := httpclient.New().Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+	require.Error(t, err)
+
+	assert.NotEmpty(t, endedSpan(t, port)["error.type"].AsString())
+
+	for attrs, active := range dataPoints(t, "http.client.active_requests", port) {
+		assert.Zero(t, active, "should release the active request, %v", attrs.Encoded(attribute.DefaultEncoder()))
+	}
+
+	durations := dataPoints(t, "http.client.request.duration", port)
+	require.Len(t, durations, 1)
+
+	for attrs := range durations {
+		errType, ok := attrs.Value("error.type")
+		assert.True(t, ok)
+		assert.NotEmpty(t, errType.AsString())
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tt := []struct {
+		desc        string
+		ctx         context.Context
+		headers     httpclient.Headers
+		traceID     trace.TraceID
+		correlation string
+	}{
+		{
+			desc:    "should propagate the trace context",
+			ctx:     trace.ContextWithSpanContext(context.Background(), spanContext),
+			traceID: spanContext.TraceID(),
+		},
+		{
+			desc:        "should propagate the correlation id",
+			ctx:         baggage.ContextWithCorrelationID(context.Background(), "order 42"),
+			correlation: "order 42",
+		},
+		{
+			desc:        "should keep the correlation id set by the caller",
+			ctx:         baggage.ContextWithCorrelationID(context.Background(), "order 42"),
+			headers:     httpclient.Headers{"X-Correlation-Key": "order 7"},
+			correlation: "order 7",
+		},
+		{
+			desc: "should not send an empty correlation id",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var received http.Header
+
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				received = r.Header
+			}))
+			defer server.Close()
+
+			_, err := httpclient.New().Get(tc.ctx, &httpclient.Request{Host: server.URL, Path: "/", Headers: tc.headers})
+			require.NoError(t, err)
+
+			if tc.traceID.IsValid() {
+				carrier := propagation.HeaderCarrier(received)
+				sent := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+
+				assert.Equal(t, tc.traceID, sent.TraceID())
+				assert.NotEqual(t, spanContext.SpanID(), sent.SpanID(), "should propagate the client span")
+			}
+
+			assert.Equal(t, tc.correlation, received.Get("X-Correlation-Key"))
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/semconv/v1.37.0/httpconv"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/env"
+)
+
+const (
+	defaultMaxIdleConns        = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultExpectContinue      = time.Second
+)
+
+type transportConfig struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	dialTimeout         time.Duration
+	tlsHandshakeTimeout time.Duration
+	http2               bool
+	proxy               func(*http.Request) (*URL.URL, error)
+	tls                 *tls.Config
+	caFile              string
+	certFile            string
+	keyFile             string
+}
+
+func defaultTransportConfig() transportConfig {
+	return transportConfig{
+		maxIdleConns:        defaultMaxIdleConns,
+		maxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+		idleConnTimeout:     defaultIdleConnTimeout,
+		dialTimeout:         defaultDialTimeout,
+		tlsHandshakeTimeout: defaultTLSHandshakeTimeout,
+		http2:               true,
+		proxy:               http.ProxyFromEnvironment,
+		caFile:              env.Get[string]("HTTP_CLIENT_TLS_CA_FILE"),
+		certFile:            env.Get[string]("HTTP_CLIENT_TLS_CERT_FILE"),
+		keyFile:             env.Get[string]("HTTP_CLIENT_TLS_KEY_FILE"),
+	}
+}
+
+// newTransport builds the transport of the client, reporting the statistics of its connection pool as metrics.
+func newTransport(cfg transportConfig) (http.RoundTripper, error) {
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.dialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(cfg.http2)
+
+	return &pooledTransport{
+		Transport: &http.Transport{
+			Proxy:                 cfg.proxy,
+			DialContext:           dialPooled(dialer.DialContext),
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   cfg.tlsHandshakeTimeout,
+			MaxIdleConns:          cfg.maxIdleConns,
+			MaxIdleConnsPerHost:   cfg.maxIdleConnsPerHost,
+			IdleConnTimeout:       cfg.idleConnTimeout,
+			ExpectContinueTimeout: defaultExpectContinue,
+			Protocols:             protocols,
+		},
+	}, nil
+}
+
+func (c transportConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.tls != nil {
+		cfg = c.tls.Clone()
+	}
+
+	if c.caFile != "" {
+		ca, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, ErrLoadTLSCredentials.WithAttribute("file", c.caFile)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if c.certFile != "" || c.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+
+	return cfg, nil
+}
+
+// failedTransport fails every request with the error that prevented the client transport from being built.
+type failedTransport struct {
+	err error
+}
+
+// RoundTrip fails with the transport error.
+func (t *failedTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// pooledTransport tracks whether the connections of its pool are active or idle.
+// HTTP/2 connections are multiplexed, so they are reported as active while open.
+type pooledTransport struct {
+	*http.Transport
+}
+
+// RoundTrip sends the request, tracking the state of the connection it is sent through.
+func (t *pooledTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var conn *pooledConn
+
+	ctx := httptrace.WithClientTrace(request.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if conn = pooledConnOf(info.Conn); conn != nil {
+				conn.setState(httpconv.ConnectionStateActive)
+			}
+		},
+		PutIdleConn: func(err error) {
+			if err == nil && conn != nil {
+				conn.setState(httpconv.ConnectionStateIdle)
+			}
+		},
+	})
+
+	return t.Transport.RoundTrip(request.WithContext(ctx))
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func dialPooled(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		host, portValue, _ := net.SplitHostPort(addr)
+		port, _ := strconv.Atoi(portValue)
+
+		return &pooledConn{Conn: conn, host: host, port: port, openedAt: time.Now()}, nil
+	}
+}
+
+// pooledConn is a connection of the pool, whose state is reported as metrics until it is closed.
+// Its state is unknown until it is first used by a request.
+type pooledConn struct {
+	net.Conn
+
+	host     string
+	port     int
+	openedAt time.Time
+
+	mu     sync.Mutex
+	state  httpconv.ConnectionStateAttr
+	closed bool
+}
+
+func pooledConnOf(conn net.Conn) *pooledConn {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	pooled, _ := conn.(*pooledConn)
+
+	return pooled
+}
+
+func (c *pooledConn) setState(state httpconv.ConnectionStateAttr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || c.state == state {
+		return
+	}
+
+	c.onStateChange(c.state, state)
+	c.state = state
+}
+
+// Close closes the connection.
+func (c *pooledConn) Close() error {
+	err := c.Conn.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		c.onClose()
+	}
+
+	return err
+}
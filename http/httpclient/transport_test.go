@@ -0,0 +1,285 @@
+package httpclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+)
+
+// writePEM writes the given PEM blocks into a new file of the test temp dir, returning its path.
+func writePEM(t *testing.T, name string, blocks ...*pem.Block) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	for _, block := range blocks {
+		require.NoError(t, pem.Encode(file, block))
+	}
+
+	return path
+}
+
+// serverCA writes the certificate of the TLS server into a PEM file, so that clients trust it.
+func serverCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	return writePEM(t, "ca.pem", &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// clientCertificate creates a self-signed client certificate, returning its parsed form and the paths of the PEM
+// files holding it and its key.
+func clientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := writePEM(t, "client.pem", &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyFile := writePEM(t, "client-key.pem", &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return cert, certFile, keyFile
+}
+
+func TestNewE(t *testing.T) {
+	invalid := writePEM(t, "invalid.pem")
+	_, certFile, keyFile := clientCertificate(t)
+
+	tt := []struct {
+		desc string
+		env  map[string]string
+		opts []httpclient.Option
+		fail bool
+	}{
+		{
+			desc: "should create clients with valid certificates",
+			opts: []httpclient.Option{httpclient.WithClientCertificate(certFile, keyFile), httpclient.WithCACertificate(certFile)},
+		},
+		{
+			desc: "should fail on missing ca certificates",
+			opts: []httpclient.Option{httpclient.WithCACertificate(filepath.Join(t.TempDir(), "missing.pem"))},
+			fail: true,
+		},
+		{
+			desc: "should fail on invalid ca certificates",
+			opts: []httpclient.Option{httpclient.WithCACertificate(invalid)},
+			fail: true,
+		},
+		{
+			desc: "should fail on invalid client certificates",
+			opts: []httpclient.Option{httpclient.WithClientCertificate(certFile, invalid)},
+			fail: true,
+		},
+		{
+			desc: "should fail on invalid certificates set by env vars",
+			env:  map[string]string{"HTTP_CLIENT_TLS_CA_FILE": invalid},
+			fail: true,
+		},
+		{
+			desc: "should ignore the certificates of custom transports",
+			opts: []httpclient.Option{
+				httpclient.WithCACertificate(invalid),
+				httpclient.WithTransport(http.DefaultTransport),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			client, err := httpclient.NewE(tc.opts...)
+
+			if !tc.fail {
+				require.NoError(t, err)
+				assert.NoError(t, client.(io.Closer).Close())
+
+				return
+			}
+
+			assert.Equal(t, errors.Code(httpclient.ErrLoadTLSCredentials), errors.Code(err))
+			assert.Nil(t, client)
+
+			_, err = httpclient.New(tc.opts...).Get(context.Background(), &httpclient.Request{Host: "https://localhost", Path: "/"})
+			assert.Equal(t, errors.Code(httpclient.ErrLoadTLSCredentials), errors.Code(err), "should fail requests on New")
+		})
+	}
+}
+
+func TestTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	ca := serverCA(t, server)
+
+	tt := []struct {
+		desc     string
+		opts     []httpclient.Option
+		expected string
+		fail     bool
+	}{
+		{
+			desc: "should not trust unknown servers",
+			fail: true,
+		},
+		{
+			desc:     "should trust servers signed by the ca certificate",
+			opts:     []httpclient.Option{httpclient.WithCACertificate(ca)},
+			expected: "HTTP/2.0",
+		},
+		{
+			desc:     "should send requests over http/1.1 disabling http/2",
+			opts:     []httpclient.Option{httpclient.WithCACertificate(ca), httpclient.WithHTTP2(false)},
+			expected: "HTTP/1.1",
+		},
+		{
+			desc:     "should apply the tls config",
+			opts:     []httpclient.Option{httpclient.WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig)},
+			expected: "HTTP/2.0",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			client, err := httpclient.NewE(tc.opts...)
+			require.NoError(t, err)
+			defer client.(io.Closer).Close()
+
+			res, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+
+			if tc.fail {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(res.Response))
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	cert, certFile, keyFile := clientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].SerialNumber.String()))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	ca := serverCA(t, server)
+
+	_, err := httpclient.New(httpclient.WithCACertificate(ca)).
+		Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+	assert.Error(t, err, "should be rejected without a client certificate")
+
+	t.Setenv("HTTP_CLIENT_TLS_CA_FILE", ca)
+	t.Setenv("HTTP_CLIENT_TLS_CERT_FILE", certFile)
+	t.Setenv("HTTP_CLIENT_TLS_KEY_FILE", keyFile)
+
+	res, err := httpclient.New().Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", string(res.Response))
+}
+
+func TestProxy(t *testing.T) {
+	var proxied *http.Request
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		proxied = r
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := URL.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	client := httpclient.New(httpclient.WithProxyURL(proxyURL))
+
+	_, err = client.Get(context.Background(), &httpclient.Request{Host: "http://api.invalid", Path: "/users"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://api.invalid/users", proxied.RequestURI)
+}
+
+func TestConnectionPoolMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	port := serverPort(t, server)
+
+	client := httpclient.New()
+
+	_, err := client.Get(context.Background(), &httpclient.Request{Host: server.URL, Path: "/"})
+	require.NoError(t, err)
+
+	states := make(map[string]float64)
+	for attrs, conns := range dataPoints(t, "http.client.open_connections", port) {
+		state, _ := attrs.Value("http.connection.state")
+		states[state.AsString()] += conns
+	}
+
+	assert.Equal(t, map[string]float64{"active": 0, "idle": 1}, states, "should keep the connection idle")
+
+	require.NoError(t, client.(io.Closer).Close())
+
+	states = make(map[string]float64)
+	for attrs, conns := range dataPoints(t, "http.client.open_connections", port) {
+		state, _ := attrs.Value("http.connection.state")
+		states[state.AsString()] += conns
+	}
+
+	assert.Equal(t, map[string]float64{"active": 0, "idle": 0}, states, "should close idle connections")
+}
@@ -1,11 +1,27 @@
 package httpclient
 
 import (
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	URL "net/url"
 
 	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/retry"
 )
 
+// idempotentMethods are the HTTP methods retried by default when retries are enabled.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
 // Headers is a map containing the relation key=value of the headers used on the http rest request.
 type Headers map[string]string
 
@@ -25,7 +41,15 @@ type Request struct {
 	QueryParams QueryParams
 	PathParams  PathParams
 
+	// BodyReader is streamed as the request body by [Client.Stream], instead of Body.
+	BodyReader io.Reader
+
 	acceptStatusCodes map[int]bool
+	retry             *bool
+	retryOpts         []retry.Option
+	timeout           *time.Duration
+	attemptTimeout    *time.Duration
+	decodeAPIError    bool
 }
 
 // Result are the params returned from the client HTTP request.
@@ -34,6 +58,59 @@ type Result struct {
 	Response   []byte
 }
 
+// StreamResult are the params returned from a streamed client HTTP request.
+// Body must be closed by the caller, which also ends the request span.
+type StreamResult struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+func (r *Request) url() (string, error) {
+	queryValues := URL.Values{}
+
+	for key, value := range r.QueryParams {
+		queryValues.Add(key, value)
+	}
+
+	uri := r.Host + r.Path
+	for p, v := range r.PathParams {
+		if strings.Contains(uri, ":"+p) {
+			uri = strings.ReplaceAll(uri, ":"+p, v)
+		}
+	}
+
+	url, err := URL.Parse(uri)
+	if err != nil {
+		return "", errors.New("error on parsing the request url")
+	}
+
+	url.RawQuery = queryValues.Encode()
+
+	return url.String(), nil
+}
+
+// accepts reports whether the given response status code is acceptable for the request.
+func (r *Request) accepts(status int) bool {
+	if len(r.acceptStatusCodes) > 0 {
+		return r.acceptStatusCodes[status]
+	}
+
+	return status < http.StatusBadRequest
+}
+
+// with returns a copy of the request with the given options applied, so that they do not leak into the request
+// given by the caller, which may be reused across calls.
+func (r *Request) with(opts ...RequestOption) *Request {
+	request := *r
+
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	return &request
+}
+
 func statusCodeToKind(code int) errors.KindType {
 	switch code {
 	case http.StatusBadRequest:
@@ -58,3 +135,21 @@ func statusCodeToKind(code int) errors.KindType {
 		return errors.KindInternal
 	}
 }
+
+// parseRetryAfter parses the Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,18 @@
+package vcr
+
+import "github.com/lcnascimento/go-kit/errors"
+
+// ErrInteractionNotFound indicates that no recorded interaction matches a request sent in replay mode.
+var ErrInteractionNotFound = errors.New("no recorded interaction matches the request").
+	WithCode("ERR_VCR_INTERACTION_NOT_FOUND").
+	WithKind(errors.KindNotFound)
+
+// ErrLoadGoldenFile indicates that the golden file could not be read in replay mode.
+var ErrLoadGoldenFile = errors.New("could not load golden file").
+	WithCode("ERR_VCR_LOAD_GOLDEN_FILE").
+	WithKind(errors.KindInternal)
+
+// ErrSaveGoldenFile indicates that the recorded interactions could not be written to the golden file.
+var ErrSaveGoldenFile = errors.New("could not save golden file").
+	WithCode("ERR_VCR_SAVE_GOLDEN_FILE").
+	WithKind(errors.KindInternal)
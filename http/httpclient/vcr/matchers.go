@@ -0,0 +1,82 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+// Matcher reports whether a request, whose body is given, matches a recorded one.
+type Matcher func(request *http.Request, body []byte, recorded *RecordedRequest) bool
+
+// MatchMethod matches requests with the same method.
+func MatchMethod(request *http.Request, _ []byte, recorded *RecordedRequest) bool {
+	return request.Method == recorded.Method
+}
+
+// MatchHost matches requests sent to the same host.
+func MatchHost(request *http.Request, _ []byte, recorded *RecordedRequest) bool {
+	url, err := URL.Parse(recorded.URL)
+	return err == nil && request.URL.Host == url.Host
+}
+
+// MatchPath matches requests with the same path.
+func MatchPath(request *http.Request, _ []byte, recorded *RecordedRequest) bool {
+	url, err := URL.Parse(recorded.URL)
+	return err == nil && request.URL.Path == url.Path
+}
+
+// MatchQuery matches requests with the same query params, regardless of their order.
+// Recorded values masked with [redact.DefaultMask] match any value.
+func MatchQuery(request *http.Request, _ []byte, recorded *RecordedRequest) bool {
+	url, err := URL.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return maps.EqualFunc(request.URL.Query(), url.Query(), func(got, want []string) bool {
+		return slices.EqualFunc(got, want, func(got, want string) bool {
+			return want == redact.DefaultMask || got == want
+		})
+	})
+}
+
+// MatchBody matches requests with the same body. JSON bodies are compared by their content,
+// regardless of the formatting and the order of their keys. Recorded JSON values masked with
+// [redact.DefaultMask] match any value.
+func MatchBody(_ *http.Request, body []byte, recorded *RecordedRequest) bool {
+	recordedBody := recorded.body()
+	if bytes.Equal(body, recordedBody) {
+		return true
+	}
+
+	var got, want any
+	if json.Unmarshal(body, &got) != nil || json.Unmarshal(recordedBody, &want) != nil {
+		return false
+	}
+
+	return matchJSON(got, want)
+}
+
+// matchJSON reports whether the decoded JSON values are equal, the masked recorded values matching any value.
+func matchJSON(got, want any) bool {
+	switch want := want.(type) {
+	case string:
+		return want == redact.DefaultMask || got == want
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		return ok && maps.EqualFunc(got, want, matchJSON)
+	case []any:
+		got, ok := got.([]any)
+		return ok && slices.EqualFunc(got, want, matchJSON)
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package vcr
+
+import (
+	"net/http"
+
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+type Option func(*config)
+
+// WithMode sets whether requests are recorded or replayed.
+// Defaults to the HTTPCLIENT_VCR_MODE env var, or to replay when it is not set.
+func WithMode(mode Mode) Option {
+	return func(c *config) {
+		c.mode = mode
+	}
+}
+
+// WithMatchers sets the matchers a request must satisfy to be served by a recorded interaction.
+// Defaults to [MatchMethod], [MatchPath] and [MatchQuery].
+func WithMatchers(matchers ...Matcher) Option {
+	return func(c *config) {
+		c.matchers = matchers
+	}
+}
+
+// WithRedactor sets the redactor of the interactions recorded in the golden file. Defaults to [redact.Default],
+// without truncating bodies. Redactors that truncate bodies, see [redact.WithMaxBodySize], truncate the recorded ones.
+func WithRedactor(redactor *redact.Redactor) Option {
+	return func(c *config) {
+		if redactor == nil {
+			return
+		}
+
+		c.redactor = redactor
+	}
+}
+
+// WithTransport sets the transport requests are sent with in record mode. Defaults to [http.DefaultTransport].
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *config) {
+		if transport == nil {
+			return
+		}
+
+		c.transport = transport
+	}
+}
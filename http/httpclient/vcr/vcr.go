@@ -0,0 +1,319 @@
+// Package vcr records the requests sent by HTTP clients, along with their responses, into golden files and
+// replays them, so that integration-style tests run offline and stay deterministic.
+//
+// Recordings are made by running the tests once with HTTPCLIENT_VCR_MODE=record, against the real services.
+// Afterwards, the tests replay the golden files without sending any request:
+//
+//	recorder, err := vcr.New("testdata/weather.json")
+//	defer recorder.Save()
+//
+//	client := httpclient.New(httpclient.WithTransport(recorder))
+package vcr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/lcnascimento/go-kit/env"
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+const base64Encoding = "base64"
+
+// Mode defines whether a Recorder records or replays requests.
+type Mode string
+
+const (
+	// ModeReplay serves recorded responses, without sending any request. Unmatched requests fail
+	// with [ErrInteractionNotFound].
+	ModeReplay Mode = "replay"
+
+	// ModeRecord sends every request and records it, along with its response.
+	// The golden file is replaced by the recorded interactions on [Recorder.Save].
+	ModeRecord Mode = "record"
+)
+
+type config struct {
+	mode      Mode
+	matchers  []Matcher
+	redactor  *redact.Redactor
+	transport http.RoundTripper
+}
+
+// Interaction is a recorded request, along with its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request stored in a golden file. Bodies that are not valid UTF-8 are base64 encoded.
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is a response stored in a golden file. Bodies that are not valid UTF-8 are base64 encoded.
+type RecordedResponse struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+type goldenFile struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an [http.RoundTripper] that records requests into a golden file, or replays them from it.
+// Requests and responses are redacted by its redactor before being recorded: the values of denied headers,
+// like Authorization, denied query params and denied keys of the bodies are masked. It is safe for concurrent use.
+type Recorder struct {
+	path string
+	cfg  config
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// New creates a new Recorder for the golden file at the given path.
+// In replay mode, the golden file is loaded right away, failing with [ErrLoadGoldenFile] when it can not be read.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path: path,
+		cfg: config{
+			mode:      Mode(env.Get("HTTPCLIENT_VCR_MODE", env.WithDefaultValue(string(ModeReplay)))),
+			matchers:  []Matcher{MatchMethod, MatchPath, MatchQuery},
+			redactor:  redact.Default(redact.WithMaxBodySize(0)),
+			transport: http.DefaultTransport,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+
+	if r.cfg.mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrLoadGoldenFile.WithCause(err).WithAttribute("vcr.golden_file", path)
+	}
+
+	var file goldenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, ErrLoadGoldenFile.WithCause(err).WithAttribute("vcr.golden_file", path)
+	}
+
+	r.interactions = file.Interactions
+	r.replayed = make([]bool, len(file.Interactions))
+
+	return r, nil
+}
+
+// Mode returns whether the Recorder records or replays requests.
+func (r *Recorder) Mode() Mode {
+	return r.cfg.mode
+}
+
+// RoundTrip replays the response of the request, or sends and records it in record mode.
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cfg.mode == ModeRecord {
+		return r.record(request, body)
+	}
+
+	return r.replay(request, body)
+}
+
+// Save writes the recorded interactions to the golden file. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.cfg.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(goldenFile{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return ErrSaveGoldenFile.WithCause(err).WithAttribute("vcr.golden_file", r.path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return ErrSaveGoldenFile.WithCause(err).WithAttribute("vcr.golden_file", r.path)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o600); err != nil {
+		return ErrSaveGoldenFile.WithCause(err).WithAttribute("vcr.golden_file", r.path)
+	}
+
+	return nil
+}
+
+func (r *Recorder) record(request *http.Request, body []byte) (*http.Response, error) {
+	sent := request.Clone(request.Context())
+	if request.Body != nil {
+		sent.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	res, err := r.cfg.transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method: request.Method,
+			URL:    r.cfg.redactor.URL(request.URL),
+			Header: r.cfg.redactor.Header(request.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     r.cfg.redactor.Header(res.Header),
+		},
+	}
+
+	interaction.Request.Body, interaction.Request.BodyEncoding = r.encodeBody(body)
+	interaction.Response.Body, interaction.Response.BodyEncoding = r.encodeBody(resBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, interaction)
+
+	return res, nil
+}
+
+// replay serves the first recorded interaction matching the request that was not replayed yet.
+// Once every matching interaction was replayed, the first one keeps being served.
+func (r *Recorder) replay(request *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+
+	for i, interaction := range r.interactions {
+		if !r.matches(request, body, &interaction.Request) {
+			continue
+		}
+
+		if !r.replayed[i] {
+			found = i
+			break
+		}
+
+		if found < 0 {
+			found = i
+		}
+	}
+
+	if found < 0 {
+		return nil, ErrInteractionNotFound.WithAttrs(
+			errors.StringAttr("http.request.method", request.Method),
+			errors.StringAttr("url.full", request.URL.String()),
+			errors.StringAttr("vcr.golden_file", r.path),
+		)
+	}
+
+	r.replayed[found] = true
+
+	recorded := r.interactions[found].Response
+	resBody := recorded.body()
+
+	// redacted bodies no longer hold the recorded length.
+	header := recorded.Header.Clone()
+	if header.Get("Content-Length") != "" {
+		header.Set("Content-Length", strconv.Itoa(len(resBody)))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(resBody)),
+		ContentLength: int64(len(resBody)),
+		Request:       request,
+	}, nil
+}
+
+func (r *Recorder) matches(request *http.Request, body []byte, recorded *RecordedRequest) bool {
+	for _, match := range r.cfg.matchers {
+		if !match(request, body, recorded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *RecordedRequest) body() []byte {
+	return decodeBody(r.Body, r.BodyEncoding)
+}
+
+func (r *RecordedResponse) body() []byte {
+	return decodeBody(r.Body, r.BodyEncoding)
+}
+
+func readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer func() { _ = request.Body.Close() }()
+
+	return io.ReadAll(request.Body)
+}
+
+// encodeBody redacts the body, base64 encoding it instead when it is not valid UTF-8.
+func (r *Recorder) encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return r.cfg.redactor.Body(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), base64Encoding
+}
+
+func decodeBody(body, encoding string) []byte {
+	if encoding != base64Encoding {
+		return []byte(body)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil
+	}
+
+	return decoded
+}
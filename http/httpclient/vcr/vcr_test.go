@@ -0,0 +1,188 @@
+package vcr_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpclient"
+	"github.com/lcnascimento/go-kit/http/httpclient/vcr"
+	"github.com/lcnascimento/go-kit/o11y/redact"
+)
+
+func login(t *testing.T, recorder *vcr.Recorder, host, key, password string) (httpclient.Result, error) {
+	t.Helper()
+
+	client := httpclient.New(
+		httpclient.WithTransport(recorder),
+		httpclient.WithAuthenticator(httpclient.APIKeyQuery("api_key", key)),
+	)
+
+	return client.Post(context.Background(), &httpclient.Request{
+		Host:    host,
+		Path:    "/login",
+		Headers: httpclient.Headers{"Authorization": "Bearer " + key},
+		Body:    []byte(`{"user":"john","password":"` + password + `"}`),
+	})
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret-session")
+		_, _ = w.Write([]byte(`{"user":"john","token":"secret-token"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "login.json")
+
+	recorder, err := vcr.New(path, vcr.WithMode(vcr.ModeRecord), vcr.WithMatchers(vcr.MatchMethod, vcr.MatchQuery, vcr.MatchBody))
+	require.NoError(t, err)
+
+	recorded, err := login(t, recorder, server.URL, "secret-key", "secret-password")
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(golden), "secret", "should redact the golden file")
+
+	server.Close()
+
+	recorder, err = vcr.New(path, vcr.WithMode(vcr.ModeReplay), vcr.WithMatchers(vcr.MatchMethod, vcr.MatchQuery, vcr.MatchBody))
+	require.NoError(t, err)
+
+	replayed, err := login(t, recorder, server.URL, "another-key", "another-password")
+	require.NoError(t, err, "should match masked values with any value")
+
+	assert.Equal(t, `{"token":"`+redact.DefaultMask+`","user":"john"}`, string(replayed.Response))
+	assert.NotEqual(t, recorded.Response, replayed.Response)
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	golden := `{"interactions":[
+		{"request":{"method":"GET","url":"http://api/users?page=1"},"response":{"status_code":200,"body":"first"}},
+		{"request":{"method":"GET","url":"http://api/users?page=1"},"response":{"status_code":200,"body":"second"}},
+		{"request":{"method":"GET","url":"http://api/files"},"response":{"status_code":200,"body":"AAE=","body_encoding":"base64"}}
+	]}`
+	require.NoError(t, os.WriteFile(path, []byte(golden), 0o600))
+
+	recorder, err := vcr.New(path)
+	require.NoError(t, err)
+	assert.Equal(t, vcr.ModeReplay, recorder.Mode())
+
+	tt := []struct {
+		desc     string
+		url      string
+		expected string
+		code     errors.CodeType
+	}{
+		{desc: "should replay the first matching interaction", url: "http://api/users?page=1", expected: "first"},
+		{desc: "should replay the next matching interaction", url: "http://api/users?page=1", expected: "second"},
+		{desc: "should keep replaying the first interaction", url: "http://api/users?page=1", expected: "first"},
+		{desc: "should decode base64 bodies", url: "http://api/files", expected: "\x00\x01"},
+		{desc: "should fail on unmatched requests", url: "http://api/users?page=2", code: errors.Code(vcr.ErrInteractionNotFound)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tc.url, nil)
+
+			res, err := recorder.RoundTrip(request)
+			if tc.code != "" {
+				assert.Equal(t, tc.code, errors.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, string(body))
+		})
+	}
+}
+
+func TestNewWithoutGoldenFile(t *testing.T) {
+	_, err := vcr.New(filepath.Join(t.TempDir(), "missing.json"), vcr.WithMode(vcr.ModeReplay))
+	assert.Equal(t, errors.Code(vcr.ErrLoadGoldenFile), errors.Code(err))
+
+	recorder, err := vcr.New(filepath.Join(t.TempDir(), "missing.json"), vcr.WithMode(vcr.ModeRecord))
+	require.NoError(t, err)
+	assert.Equal(t, vcr.ModeRecord, recorder.Mode())
+}
+
+func TestMatchers(t *testing.T) {
+	tt := []struct {
+		desc     string
+		matcher  vcr.Matcher
+		url      string
+		body     string
+		recorded vcr.RecordedRequest
+		expected bool
+	}{
+		{
+			desc:     "should match queries regardless of their order",
+			matcher:  vcr.MatchQuery,
+			url:      "http://api/users?b=2&a=1",
+			recorded: vcr.RecordedRequest{URL: "http://api/users?a=1&b=2"},
+			expected: true,
+		},
+		{
+			desc:     "should match masked query values with any value",
+			matcher:  vcr.MatchQuery,
+			url:      "http://api/users?a=1&token=abc",
+			recorded: vcr.RecordedRequest{URL: "http://api/users?a=1&token=" + redact.DefaultMask},
+			expected: true,
+		},
+		{
+			desc:     "should not match different queries",
+			matcher:  vcr.MatchQuery,
+			url:      "http://api/users?a=2",
+			recorded: vcr.RecordedRequest{URL: "http://api/users?a=1"},
+		},
+		{
+			desc:     "should match json bodies regardless of their formatting",
+			matcher:  vcr.MatchBody,
+			body:     `{"b":[1,2], "a":{"c":true}}`,
+			recorded: vcr.RecordedRequest{Body: `{"a":{"c":true},"b":[1,2]}`},
+			expected: true,
+		},
+		{
+			desc:     "should match masked json values with any value",
+			matcher:  vcr.MatchBody,
+			body:     `{"user":{"password":"123"}}`,
+			recorded: vcr.RecordedRequest{Body: `{"user":{"password":"` + redact.DefaultMask + `"}}`},
+			expected: true,
+		},
+		{
+			desc:     "should not match different bodies",
+			matcher:  vcr.MatchBody,
+			body:     `{"user":"john"}`,
+			recorded: vcr.RecordedRequest{Body: `{"user":"jane"}`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			url := tc.url
+			if url == "" {
+				url = "http://api/"
+			}
+
+			request := httptest.NewRequest(http.MethodPost, url, strings.NewReader(tc.body))
+
+			assert.Equal(t, tc.expected, tc.matcher(request, []byte(tc.body), &tc.recorded))
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package httpserver
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lcnascimento/go-kit/errors"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+// paramSource is the part of a request a struct field is bound from, named after its tag.
+type paramSource string
+
+const (
+	sourcePath   paramSource = "path"
+	sourceQuery  paramSource = "query"
+	sourceHeader paramSource = "header"
+)
+
+var (
+	paramSources = []paramSource{sourcePath, sourceQuery, sourceHeader}
+
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+type boundField struct {
+	index  []int
+	source paramSource
+	name   string
+}
+
+// binder binds the path params, query params and headers of requests into the fields of a struct,
+// following their path, query and header tags.
+type binder struct {
+	fields []boundField
+}
+
+// newBinder builds the binder of the given type, which binds nothing when it is not a struct.
+func newBinder(t reflect.Type) *binder {
+	b := &binder{}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return b
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, source := range paramSources {
+			if name := field.Tag.Get(string(source)); name != "" && name != "-" {
+				b.fields = append(b.fields, boundField{index: field.Index, source: source, name: name})
+			}
+		}
+	}
+
+	return b
+}
+
+// bind sets the bound fields of the struct v points to. Params absent from the request leave their fields untouched.
+func (b *binder) bind(r *http.Request, v reflect.Value) error {
+	if len(b.fields) == 0 {
+		return nil
+	}
+
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	for _, field := range b.fields {
+		var values []string
+
+		switch field.source {
+		case sourcePath:
+			if value, ok := vars[field.name]; ok {
+				values = []string{value}
+			}
+		case sourceQuery:
+			values = query[field.name]
+		case sourceHeader:
+			values = r.Header.Values(field.name)
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		fv, err := fieldByIndex(v, field.index)
+		if err != nil {
+			return util.ErrBindRequestParam.WithCause(err).WithAttribute(string(field.source), field.name)
+		}
+
+		if err := setValues(fv, values); err != nil {
+			return util.ErrBindRequestParam.WithCause(err).WithAttribute(string(field.source), field.name)
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns the nested field of v with the given index, allocating the nil embedded structs it is
+// promoted through.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errors.New("could not allocate unexported embedded struct %s", v.Type())
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, nil
+}
+
+// setValues sets a field from the values of a param, every one of them being kept when the field is a slice.
+func setValues(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice && !fv.Type().Implements(textUnmarshalerType) && fv.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+
+		fv.Set(slice)
+
+		return nil
+	}
+
+	return setValue(fv, values[0])
+}
+
+func setValue(fv reflect.Value, value string) error {
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+
+		fv.Set(ptr)
+
+		return nil
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)) //nolint:forcetypeassert // OK
+	}
+
+	if fv.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(int64(duration))
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		fv.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		fv.SetFloat(parsed)
+	default:
+		return errors.New("unsupported param type %s", fv.Type())
+	}
+
+	return nil
+}
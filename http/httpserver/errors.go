@@ -0,0 +1,10 @@
+package httpserver
+
+import (
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+// ErrLoadTLSCredentials indicates that the TLS certificates configured for the server could not be loaded.
+var ErrLoadTLSCredentials = errors.New("could not load TLS credentials").
+	WithCode("ERR_LOAD_TLS_CREDENTIALS").
+	WithKind(errors.KindInvalidInput)
@@ -0,0 +1,177 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/lcnascimento/go-kit/errors"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+// Validator validates the requests decoded by [Handle]. It is implemented by the validator module.
+type Validator interface {
+	Struct(s any) error
+}
+
+type handlerConfig struct {
+	status      int
+	operationID string
+	summary     string
+	description string
+	tags        []string
+}
+
+// HandlerOption configures a handler built by [Handle].
+type HandlerOption func(*handlerConfig)
+
+// WithStatusCode sets the status code of successful responses. Defaults to 200.
+// Responses with status 204 are written without a body.
+func WithStatusCode(status int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.status = status
+	}
+}
+
+// WithOperationID sets the ID of the operation of the handler in the OpenAPI document, see [OpenAPI].
+func WithOperationID(id string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.operationID = id
+	}
+}
+
+// WithSummary sets the summary of the operation of the handler in the OpenAPI document, see [OpenAPI].
+func WithSummary(summary string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.summary = summary
+	}
+}
+
+// WithDescription sets the description of the operation of the handler in the OpenAPI document, see [OpenAPI].
+func WithDescription(description string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.description = description
+	}
+}
+
+// WithTags sets the tags grouping the operation of the handler in the OpenAPI document, see [OpenAPI].
+func WithTags(tags ...string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.tags = tags
+	}
+}
+
+// handler is an [http.Handler] whose request and response types are known, so that it can be documented.
+type handler[Req, Resp any] struct {
+	fn     func(ctx context.Context, req Req) (Resp, error)
+	cfg    *handlerConfig
+	binder *binder
+}
+
+// Handle adapts fn into an [http.Handler]. The request is decoded from the JSON body, failing with
+// [util.ErrParseRequestBody], and from the path params, query params and headers named by the path, query and
+// header tags of its fields, failing with [util.ErrBindRequestParam]:
+//
+//	type GetUserRequest struct {
+//		ID     string   `path:"id"`
+//		Fields []string `query:"fields"`
+//		Tenant string   `header:"X-Tenant-ID" validate:"required"`
+//	}
+//
+// The request is then validated by the validator of the server, see [WithValidator], before being handed to fn.
+// The response of fn is written as JSON, while its errors are written by [util.WriteError].
+func Handle[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error), opts ...HandlerOption) http.Handler {
+	cfg := &handlerConfig{status: http.StatusOK}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &handler[Req, Resp]{fn: fn, cfg: cfg, binder: newBinder(reflect.TypeFor[Req]())}
+}
+
+// ServeHTTP decodes the request, hands it to the handler function and writes its response.
+func (h *handler[Req, Resp]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	reqType := reflect.TypeFor[Req]()
+
+	req, err := decodeRequest[Req](r, reqType, h.binder)
+	if err != nil {
+		util.WriteError(ctx, w, err)
+		return
+	}
+
+	if validator, ok := validatorFromContext(ctx); ok && isStruct(reqType) {
+		if err := validator.Struct(req); err != nil {
+			util.WriteError(ctx, w, err)
+			return
+		}
+	}
+
+	res, err := h.fn(ctx, req)
+	if err != nil {
+		util.WriteError(ctx, w, err)
+		return
+	}
+
+	if h.cfg.status == http.StatusNoContent {
+		util.WriteResponse(w, h.cfg.status, nil)
+		return
+	}
+
+	util.WriteResponse(w, h.cfg.status, res)
+}
+
+func decodeRequest[Req any](r *http.Request, reqType reflect.Type, binder *binder) (Req, error) {
+	var req Req
+
+	target := reflect.ValueOf(&req).Elem()
+	if reqType.Kind() == reflect.Pointer {
+		target.Set(reflect.New(reqType.Elem()))
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		if err := json.NewDecoder(r.Body).Decode(target.Addr().Interface()); err != nil && !errors.Is(err, io.EOF) {
+			return req, util.ErrParseRequestBody.WithCause(err)
+		}
+	}
+
+	// a null body decodes into a nil pointer, which is handled like an empty body.
+	if reqType.Kind() == reflect.Pointer && target.IsNil() {
+		target.Set(reflect.New(reqType.Elem()))
+	}
+
+	if err := binder.bind(r, target); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
+
+type validatorKey struct{}
+
+func validatorFromContext(ctx context.Context) (Validator, bool) {
+	validator, ok := ctx.Value(validatorKey{}).(Validator)
+	return validator, ok
+}
+
+// withValidator exposes the validator of the server to the handlers built by [Handle].
+func withValidator(validator Validator, next http.Handler) http.Handler {
+	if validator == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), validatorKey{}, validator)))
+	})
+}
@@ -0,0 +1,195 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+
+	"github.com/lcnascimento/go-kit/http/httpserver"
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+var errUserNotFound = errors.New("user not found").WithCode("ERR_USER_NOT_FOUND").WithKind(errors.KindNotFound)
+
+// Page is exported, since nil embedded pointers to unexported structs can not be allocated.
+type Page struct {
+	Limit int `query:"limit"`
+}
+
+type saveUserRequest struct {
+	*Page
+
+	Tenant string        `path:"tenant"`
+	ID     int64         `path:"id"`
+	Fields []string      `query:"fields"`
+	Since  time.Duration `query:"since"`
+	Active *bool         `query:"active"`
+	Locale string        `header:"Accept-Language"`
+	Name   string        `json:"name"`
+}
+
+// requiredName is a validator requiring the name of the requests to be set.
+type requiredName struct{}
+
+func (requiredName) Struct(s any) error {
+	if req, ok := s.(*saveUserRequest); ok && req.Name == "" {
+		return errors.New("name is required").WithCode("ERR_VALIDATION").WithKind(errors.KindUnprocessable)
+	}
+
+	return nil
+}
+
+func userRoutes(router *mux.Router) {
+	echo := func(_ context.Context, req *saveUserRequest) (*saveUserRequest, error) {
+		if req.Name == "ghost" {
+			return nil, errUserNotFound
+		}
+
+		return req, nil
+	}
+
+	router.Handle("/tenants/{tenant}/users/{id}", httpserver.Handle(echo)).Methods(http.MethodPut)
+	router.Handle("/tenants/{tenant}/users/{id}", httpserver.Handle(
+		func(context.Context, saveUserRequest) (any, error) { return nil, nil },
+		httpserver.WithStatusCode(http.StatusNoContent),
+	)).Methods(http.MethodDelete)
+}
+
+func do(t *testing.T, method, url, body string, headers http.Header) (int, []byte) {
+	t.Helper()
+
+	request, err := http.NewRequest(method, url, strings.NewReader(body)) //nolint:noctx // tests do not need a context.
+	require.NoError(t, err)
+
+	request.Header = headers
+
+	res, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res.StatusCode, raw
+}
+
+func TestHandle(t *testing.T) {
+	active := true
+
+	tt := []struct {
+		desc     string
+		opts     []httpserver.Option
+		method   string
+		path     string
+		body     string
+		headers  http.Header
+		status   int
+		expected *saveUserRequest
+		code     errors.CodeType
+	}{
+		{
+			desc:    "should bind the body, path params, query params and headers",
+			method:  http.MethodPut,
+			path:    "/tenants/acme/users/42?fields=name&fields=email&since=1h&active=true&limit=10",
+			body:    `{"name":"john"}`,
+			headers: http.Header{"Accept-Language": {"pt-BR"}},
+			status:  http.StatusOK,
+			expected: &saveUserRequest{
+				Page:   &Page{Limit: 10},
+				Tenant: "acme",
+				ID:     42,
+				Fields: []string{"name", "email"},
+				Since:  time.Hour,
+				Active: &active,
+				Locale: "pt-BR",
+				Name:   "john",
+			},
+		},
+		{
+			desc:     "should leave the fields of absent params untouched",
+			method:   http.MethodPut,
+			path:     "/tenants/acme/users/42",
+			status:   http.StatusOK,
+			expected: &saveUserRequest{Tenant: "acme", ID: 42},
+		},
+		{
+			desc:     "should handle null bodies like empty ones",
+			method:   http.MethodPut,
+			path:     "/tenants/acme/users/42",
+			body:     `null`,
+			status:   http.StatusOK,
+			expected: &saveUserRequest{Tenant: "acme", ID: 42},
+		},
+		{
+			desc:   "should fail on invalid params",
+			method: http.MethodPut,
+			path:   "/tenants/acme/users/john",
+			status: http.StatusBadRequest,
+			code:   errors.Code(util.ErrBindRequestParam),
+		},
+		{
+			desc:   "should fail on invalid bodies",
+			method: http.MethodPut,
+			path:   "/tenants/acme/users/42",
+			body:   `{"name":`,
+			status: http.StatusBadRequest,
+			code:   errors.Code(util.ErrParseRequestBody),
+		},
+		{
+			desc:   "should validate requests with the validator of the server",
+			opts:   []httpserver.Option{httpserver.WithValidator(requiredName{})},
+			method: http.MethodPut,
+			path:   "/tenants/acme/users/42",
+			status: http.StatusUnprocessableEntity,
+			code:   "ERR_VALIDATION",
+		},
+		{
+			desc:   "should write the errors of the handler",
+			method: http.MethodPut,
+			path:   "/tenants/acme/users/42",
+			body:   `{"name":"ghost"}`,
+			status: http.StatusNotFound,
+			code:   errors.Code(errUserNotFound),
+		},
+		{
+			desc:   "should write responses without a body",
+			method: http.MethodDelete,
+			path:   "/tenants/acme/users/42",
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, url := startServer(t, userRoutes, tc.opts...)
+
+			status, body := do(t, tc.method, url+tc.path, tc.body, tc.headers)
+			require.Equal(t, tc.status, status, string(body))
+
+			switch {
+			case tc.code != "":
+				var apiErr util.APIError
+				require.NoError(t, json.Unmarshal(body, &apiErr))
+
+				assert.Equal(t, string(tc.code), apiErr.Code)
+			case tc.expected != nil:
+				var got saveUserRequest
+				require.NoError(t, json.Unmarshal(body, &got))
+
+				assert.Equal(t, tc.expected, &got)
+			default:
+				assert.Empty(t, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	oMetric "go.opentelemetry.io/otel/metric"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+// HealthStatus is the status of a health check, or of the aggregation of many of them.
+type HealthStatus string
+
+const (
+	// HealthStatusOK means the check succeeded.
+	HealthStatusOK HealthStatus = "ok"
+
+	// HealthStatusFailing means the check failed, or did not finish before its timeout.
+	HealthStatusFailing HealthStatus = "failing"
+)
+
+// HealthCheckFunc checks the health of a dependency of the server, like a database or a downstream service.
+// It must return an error when the dependency is unhealthy, and give up once its context is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+type healthCheck struct {
+	name     string
+	check    HealthCheckFunc
+	timeout  time.Duration
+	liveness bool
+
+	// last is the result of the last run of the check, reported as a metric.
+	last atomic.Pointer[HealthCheckResult]
+}
+
+// HealthReport is the aggregated result of the health checks, served as JSON by the health endpoints.
+type HealthReport struct {
+	Status HealthStatus                 `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is the result of a single health check. The errors of failing checks are logged rather
+// than served, since the health endpoints are not authenticated.
+type HealthCheckResult struct {
+	Status   HealthStatus `json:"status"`
+	Duration string       `json:"duration"`
+}
+
+// health is the registry of the health checks of the server, along with its readiness.
+type health struct {
+	mu     sync.RWMutex
+	checks []*healthCheck
+
+	ready        atomic.Bool
+	registration oMetric.Registration
+}
+
+func newHealth() *health {
+	h := &health{}
+	h.registration = h.onCreate()
+
+	return h
+}
+
+// close stops reporting the health of the server.
+func (h *health) close() error {
+	if h.registration == nil {
+		return nil
+	}
+
+	return h.registration.Unregister()
+}
+
+func (h *health) register(check *healthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if check.timeout <= 0 {
+		check.timeout = defaultHealthCheckTimeout
+	}
+
+	h.checks = append(h.checks, check)
+}
+
+// registered returns the registered checks, the readiness ones being skipped when livenessOnly is set.
+func (h *health) registered(livenessOnly bool) []*healthCheck {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	checks := make([]*healthCheck, 0, len(h.checks))
+
+	for _, check := range h.checks {
+		if !livenessOnly || check.liveness {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+// run runs the registered checks concurrently, the readiness ones being skipped when livenessOnly is set.
+func (h *health) run(ctx context.Context, livenessOnly bool) HealthReport {
+	checks := h.registered(livenessOnly)
+
+	results := make([]HealthCheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			results[i] = check.run(ctx)
+		})
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthStatusOK, Checks: make(map[string]HealthCheckResult, len(checks))}
+
+	for i, check := range checks {
+		report.Checks[check.name] = results[i]
+
+		if results[i].Status != HealthStatusOK {
+			report.Status = HealthStatusFailing
+		}
+	}
+
+	return report
+}
+
+// readiness runs every check, failing regardless of their results once the server is shutting down.
+func (h *health) readiness(ctx context.Context) HealthReport {
+	report := h.run(ctx, false)
+	if !h.ready.Load() {
+		report.Status = HealthStatusFailing
+	}
+
+	return report
+}
+
+func (c *healthCheck) run(ctx context.Context) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	result := HealthCheckResult{Status: HealthStatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = HealthStatusFailing
+
+		c.onFailure(ctx, err)
+	}
+
+	c.last.Store(&result)
+
+	return result
+}
+
+// handler serves the health endpoints, handing any other request to next.
+// Probes skip the middlewares of the server, so that they are not recorded as regular requests.
+func (h *health) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report HealthReport
+
+		switch {
+		case r.Method != http.MethodGet && r.Method != http.MethodHead:
+			next.ServeHTTP(w, r)
+			return
+		case r.URL.Path == healthzPath:
+			report = h.run(r.Context(), true)
+		case r.URL.Path == readyzPath:
+			report = h.readiness(r.Context())
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		status := http.StatusOK
+		if report.Status != HealthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		util.WriteResponse(w, status, report)
+	})
+}
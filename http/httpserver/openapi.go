@@ -0,0 +1,231 @@
+package httpserver
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/openapi"
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+const (
+	openAPIPath       = "/openapi.json"
+	jsonContentType   = "application/json"
+	errorResponseName = "Error"
+)
+
+// pathVariable matches the variables of mux path templates, along with their patterns.
+var pathVariable = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// documented is implemented by the handlers whose operation can be described in an OpenAPI document.
+type documented interface {
+	operation(schemas *openapi.Schemas, method string) *openapi.Operation
+}
+
+// OpenAPI builds the OpenAPI 3.1 document of the routes of the router handled by [Handle], deriving the schemas
+// of their requests and responses from their Go types. Routes that do not restrict their methods are not
+// documented. Errors are documented by the default response of each operation, holding the APIError schema.
+//
+// The document can be exported from a test, by registering the routes of the server on a new router:
+//
+//	router := mux.NewRouter()
+//	_ = registerRoutes(router)
+//
+//	doc, err := httpserver.OpenAPI(router, openapi.Info{Title: "Users API", Version: "1.0.0"})
+func OpenAPI(router *mux.Router, info openapi.Info) (*openapi.Document, error) {
+	schemas := openapi.NewSchemas()
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info:    info,
+		Paths:   make(map[string]*openapi.PathItem),
+		Components: &openapi.Components{
+			Responses: map[string]*openapi.Response{errorResponseName: errorResponse(schemas)},
+		},
+	}
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		h, ok := route.GetHandler().(documented)
+		if !ok {
+			return nil
+		}
+
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil //nolint:nilerr // routes without path are not documented
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil //nolint:nilerr // routes without methods are not documented
+		}
+
+		path := pathVariable.ReplaceAllString(tpl, "{$1}")
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[path] = item
+		}
+
+		for _, method := range methods {
+			(*item)[strings.ToLower(method)] = h.operation(schemas, method)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Components.Schemas = schemas.Components()
+
+	return doc, nil
+}
+
+// serveOpenAPI serves the OpenAPI document of the routes registered so far at /openapi.json.
+func serveOpenAPI(router *mux.Router, info openapi.Info) error {
+	doc, err := OpenAPI(router, info)
+	if err != nil {
+		return err
+	}
+
+	router.Handle(openAPIPath, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		util.WriteResponse(w, http.StatusOK, doc)
+	})).Methods(http.MethodGet)
+
+	return nil
+}
+
+// errorResponse describes the responses written by [util.WriteError], whose status codes follow their kinds.
+func errorResponse(schemas *openapi.Schemas) *openapi.Response {
+	kindsByStatus := util.ErrorKindsByStatusCode()
+	statuses := make([]string, 0, len(kindsByStatus))
+
+	for _, status := range slices.Sorted(maps.Keys(kindsByStatus)) {
+		kinds := make([]string, 0, len(kindsByStatus[status]))
+		for _, kind := range kindsByStatus[status] {
+			kinds = append(kinds, string(kind))
+		}
+
+		statuses = append(statuses, fmt.Sprintf("%d for %s", status, strings.Join(kinds, ", ")))
+	}
+
+	return &openapi.Response{
+		Description: "Error, written with the status code of its kind: " + strings.Join(statuses, "; "),
+		Content:     map[string]*openapi.MediaType{jsonContentType: {Schema: schemas.Of(reflect.TypeFor[util.APIError]())}},
+	}
+}
+
+func (h *handler[Req, Resp]) operation(schemas *openapi.Schemas, method string) *openapi.Operation {
+	reqType := reflect.TypeFor[Req]()
+
+	op := &openapi.Operation{
+		OperationID: h.cfg.operationID,
+		Summary:     h.cfg.summary,
+		Description: h.cfg.description,
+		Tags:        h.cfg.tags,
+		Parameters:  parameters(schemas, reqType),
+		Responses:   make(map[string]*openapi.Response),
+	}
+
+	if method != http.MethodGet && method != http.MethodHead {
+		op.RequestBody = requestBody(schemas, reqType)
+	}
+
+	success := &openapi.Response{Description: http.StatusText(h.cfg.status)}
+	if h.cfg.status != http.StatusNoContent {
+		success.Content = map[string]*openapi.MediaType{
+			jsonContentType: {Schema: schemas.Of(reflect.TypeFor[Resp]())},
+		}
+	}
+
+	op.Responses[strconv.Itoa(h.cfg.status)] = success
+	op.Responses["default"] = openapi.ResponseRef(errorResponseName)
+
+	return op
+}
+
+// parameters describes the fields of the request bound from its path params, query params and headers.
+func parameters(schemas *openapi.Schemas, reqType reflect.Type) []*openapi.Parameter {
+	if !isStruct(reqType) {
+		return nil
+	}
+
+	for reqType.Kind() == reflect.Pointer {
+		reqType = reqType.Elem()
+	}
+
+	var params []*openapi.Parameter
+
+	for _, field := range reflect.VisibleFields(reqType) {
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, source := range paramSources {
+			name := field.Tag.Get(string(source))
+			if name == "" || name == "-" {
+				continue
+			}
+
+			schema, required := schemas.Field(field)
+
+			params = append(params, &openapi.Parameter{
+				Name:     name,
+				In:       string(source),
+				Required: required || source == sourcePath,
+				Schema:   schema,
+			})
+		}
+	}
+
+	return params
+}
+
+// requestBody describes the JSON body of the request, made of the fields not bound from other parts of the request.
+func requestBody(schemas *openapi.Schemas, reqType reflect.Type) *openapi.RequestBody {
+	if !isStruct(reqType) {
+		return &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]*openapi.MediaType{jsonContentType: {Schema: schemas.Of(reqType)}},
+		}
+	}
+
+	var bound bool
+
+	schema := schemas.Object(reqType, func(field reflect.StructField) bool {
+		for _, source := range paramSources {
+			if name := field.Tag.Get(string(source)); name != "" && name != "-" {
+				bound = true
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+
+	required := len(schema.Required) > 0
+
+	// Requests without bound fields are described by the schema of their type, shared across operations.
+	if !bound {
+		schema = schemas.Of(reqType)
+	}
+
+	return &openapi.RequestBody{
+		Required: required,
+		Content:  map[string]*openapi.MediaType{jsonContentType: {Schema: schema}},
+	}
+}
@@ -0,0 +1,324 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	invalidNameSequence = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// validateFormats maps the validate tags describing string formats into their JSON Schema formats.
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"http_url": "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// Schemas derives the schemas of Go types. Named struct types are registered as component schemas,
+// being referenced wherever they are used.
+type Schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+// NewSchemas creates a new Schemas, without components.
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// Components returns the component schemas registered so far.
+func (s *Schemas) Components() map[string]*Schema {
+	return maps.Clone(s.components)
+}
+
+// Of returns the schema of the given type, following how [encoding/json] encodes it.
+func (s *Schemas) Of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: s.Of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.Of(t.Elem())}
+	case reflect.Struct:
+		return s.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// Object returns the inline schema of the given struct type, holding only the fields accepted by include.
+func (s *Schemas) Object(t reflect.Type, include func(field reflect.StructField) bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range reflect.VisibleFields(t) {
+		name, ok := jsonName(field)
+		if !ok || !promoted(t, field) || !include(field) {
+			continue
+		}
+
+		property, required := s.Field(field)
+		schema.Properties[name] = property
+
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// Field returns the schema of a struct field, constrained by its validate tag and exemplified by its example tag,
+// and whether the field is required.
+func (s *Schemas) Field(field reflect.StructField) (*Schema, bool) {
+	schema := s.Of(field.Type)
+
+	rules := validateRules(field.Tag.Get("validate"))
+	example := field.Tag.Get("example")
+
+	_, required := rules["required"]
+
+	if schema.Ref != "" || (len(rules) == 0 && example == "") {
+		return schema, required
+	}
+
+	constrained := *schema
+	constrained.constrain(rules)
+
+	if example != "" {
+		constrained.Example = parseExample(constrained.Type, example)
+	}
+
+	return &constrained, required
+}
+
+func (s *Schemas) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return s.Object(t, func(reflect.StructField) bool { return true })
+	}
+
+	if name, ok := s.names[t]; ok {
+		return SchemaRef(name)
+	}
+
+	name := s.nameOf(t)
+	s.names[t] = name
+
+	// The component is registered before its fields are derived, so that recursive types reference it.
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.Object(t, func(reflect.StructField) bool { return true })
+
+	return SchemaRef(name)
+}
+
+// nameOf names the component of a type after the type, prefixing it with its package when the name is taken.
+func (s *Schemas) nameOf(t reflect.Type) string {
+	name := invalidNameSequence.ReplaceAllString(t.Name(), "_")
+	if _, taken := s.components[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	base := invalidNameSequence.ReplaceAllString(pkg, "_") + "." + name
+
+	name = base
+	for i := 2; ; i++ {
+		if _, taken := s.components[name]; !taken {
+			return name
+		}
+
+		name = base + "_" + strconv.Itoa(i)
+	}
+}
+
+func (schema *Schema) constrain(rules map[string]*string) {
+	for rule, param := range rules {
+		if format, ok := validateFormats[rule]; ok && schema.Type == "string" {
+			schema.Format = format
+			continue
+		}
+
+		if param == nil {
+			continue
+		}
+
+		switch rule {
+		case "oneof":
+			schema.Enum = nil
+			for value := range strings.FieldsSeq(*param) {
+				schema.Enum = append(schema.Enum, parseExample(schema.Type, value))
+			}
+		case "min", "gte":
+			schema.setBound(*param, &schema.Minimum, &schema.MinLength, &schema.MinItems)
+		case "max", "lte":
+			schema.setBound(*param, &schema.Maximum, &schema.MaxLength, &schema.MaxItems)
+		case "len":
+			schema.setBound(*param, &schema.Minimum, &schema.MinLength, &schema.MinItems)
+			schema.setBound(*param, &schema.Maximum, &schema.MaxLength, &schema.MaxItems)
+		case "gt":
+			schema.setBound(*param, &schema.ExclusiveMinimum, nil, nil)
+		case "lt":
+			schema.setBound(*param, &schema.ExclusiveMaximum, nil, nil)
+		}
+	}
+}
+
+// setBound sets the bound of a number, or the length bound of a string or array.
+func (schema *Schema) setBound(param string, number **float64, length, items **int) {
+	switch schema.Type {
+	case "integer", "number":
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			*number = &value
+		}
+	case "string":
+		if value, err := strconv.Atoi(param); err == nil && length != nil {
+			*length = &value
+		}
+	case "array":
+		if value, err := strconv.Atoi(param); err == nil && items != nil {
+			*items = &value
+		}
+	}
+}
+
+// validateRules parses the rules of a validate tag that apply to the field itself, the ones after dive
+// applying to its elements.
+func validateRules(tag string) map[string]*string {
+	rules := make(map[string]*string)
+
+	for rule := range strings.SplitSeq(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+
+		name, param, ok := strings.Cut(rule, "=")
+		if name == "" {
+			continue
+		}
+
+		if ok {
+			rules[name] = &param
+		} else {
+			rules[name] = nil
+		}
+	}
+
+	return rules
+}
+
+func parseExample(schemaType, value string) any {
+	switch schemaType {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+
+	return value
+}
+
+// promoted reports whether a field is encoded by [encoding/json] along with the fields of t, which is not the
+// case of the fields of embedded structs that are encoded under their own name.
+func promoted(t reflect.Type, field reflect.StructField) bool {
+	for i := 1; i < len(field.Index); i++ {
+		if _, ok := jsonName(t.FieldByIndex(field.Index[:i])); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonName returns the name of a field encoded by [encoding/json], reporting false when it is not encoded.
+// Embedded structs without a name are not encoded themselves, as their fields are promoted.
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+
+	if field.Anonymous && name == "" {
+		t := field.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t.Kind() == reflect.Struct || !field.IsExported() {
+			return "", false
+		}
+	}
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
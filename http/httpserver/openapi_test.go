@@ -0,0 +1,146 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/errors"
+
+	"github.com/lcnascimento/go-kit/http/httpserver"
+	"github.com/lcnascimento/go-kit/http/httpserver/openapi"
+)
+
+type user struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// documentedRoutes register the user routes, along with routes that can not be documented.
+func documentedRoutes(router *mux.Router) {
+	userRoutes(router)
+
+	router.Handle("/users/{id:[0-9]+}", httpserver.Handle(
+		func(context.Context, struct {
+			ID int64 `path:"id"`
+		}) (*user, error) {
+			return &user{}, nil
+		},
+		httpserver.WithOperationID("getUser"),
+		httpserver.WithSummary("Gets a user"),
+		httpserver.WithTags("users"),
+	)).Methods(http.MethodGet)
+
+	router.Handle("/any", httpserver.Handle(func(context.Context, any) (any, error) { return nil, nil }))
+	router.HandleFunc("/plain", func(http.ResponseWriter, *http.Request) {}).Methods(http.MethodGet)
+}
+
+func TestOpenAPI(t *testing.T) {
+	info := openapi.Info{Title: "Users API", Version: "1.0.0"}
+
+	_, url := startServer(t, documentedRoutes, httpserver.WithOpenAPI(info))
+
+	status, body := get(t, url+"/openapi.json")
+	require.Equal(t, http.StatusOK, status)
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(body, &doc))
+
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Equal(t, info, doc.Info)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+
+	assert.ElementsMatch(t, []string{"/tenants/{tenant}/users/{id}", "/users/{id}"}, paths, "should document only the routes of handlers")
+
+	t.Run("should document the params and the body of requests", func(t *testing.T) {
+		put := (*doc.Paths["/tenants/{tenant}/users/{id}"])["put"]
+		require.NotNil(t, put)
+
+		params := make(map[string]string)
+		for _, param := range put.Parameters {
+			params[param.Name] = param.In
+
+			if param.In == "path" {
+				assert.True(t, param.Required, param.Name)
+			}
+		}
+
+		assert.Equal(t, map[string]string{
+			"tenant":          "path",
+			"id":              "path",
+			"fields":          "query",
+			"since":           "query",
+			"active":          "query",
+			"limit":           "query",
+			"Accept-Language": "header",
+		}, params)
+
+		require.NotNil(t, put.RequestBody)
+
+		schema := put.RequestBody.Content["application/json"].Schema
+		assert.Equal(t, []string{"name"}, keys(schema.Properties), "should leave bound fields out of the body")
+	})
+
+	t.Run("should document the responses of operations", func(t *testing.T) {
+		put := (*doc.Paths["/tenants/{tenant}/users/{id}"])["put"]
+		assert.Equal(t, "#/components/schemas/saveUserRequest", put.Responses["200"].Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/responses/Error", put.Responses["default"].Ref)
+
+		del := (*doc.Paths["/tenants/{tenant}/users/{id}"])["delete"]
+		require.Contains(t, del.Responses, "204")
+		assert.Nil(t, del.Responses["204"].Content, "should document no content responses without a body")
+		assert.Equal(t, "#/components/responses/Error", del.Responses["default"].Ref)
+	})
+
+	t.Run("should document the metadata of operations", func(t *testing.T) {
+		op := (*doc.Paths["/users/{id}"])["get"]
+		require.NotNil(t, op)
+
+		assert.Equal(t, "getUser", op.OperationID)
+		assert.Equal(t, "Gets a user", op.Summary)
+		assert.Equal(t, []string{"users"}, op.Tags)
+		assert.Nil(t, op.RequestBody, "should not document bodies of get requests")
+		assert.Equal(t, "#/components/schemas/user", op.Responses["200"].Content["application/json"].Schema.Ref)
+	})
+
+	t.Run("should document errors by a single component response", func(t *testing.T) {
+		assert.Equal(t, []string{"Error"}, keys(doc.Components.Responses))
+
+		errorResponse := doc.Components.Responses["Error"]
+		assert.Equal(t, "#/components/schemas/APIError", errorResponse.Content["application/json"].Schema.Ref)
+		assert.Contains(t, errorResponse.Description, "400 for "+string(errors.KindInvalidInput))
+		assert.Contains(t, errorResponse.Description, "404 for "+string(errors.KindNotFound))
+
+		assert.Contains(t, doc.Components.Schemas, "APIError")
+		assert.Contains(t, doc.Components.Schemas, "user")
+	})
+}
+
+func TestOpenAPIFromRouter(t *testing.T) {
+	router := mux.NewRouter()
+	documentedRoutes(router)
+
+	doc, err := httpserver.OpenAPI(router, openapi.Info{Title: "Users API", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	assert.Len(t, doc.Paths, 2)
+	assert.NotContains(t, doc.Paths, "/openapi.json", "should not serve the document without WithOpenAPI")
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+
+	return out
+}
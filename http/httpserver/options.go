@@ -1,8 +1,14 @@
 package httpserver
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net"
 	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/openapi"
 )
 
 type Option func(*Server)
@@ -18,3 +24,137 @@ func WithReadHeaderTimeout(timeout time.Duration) Option {
 		s.server.ReadHeaderTimeout = timeout
 	}
 }
+
+// WithDrainDelay sets for how long the server keeps serving requests on [Server.Shutdown], after its readiness
+// starts failing. A zero delay shuts the server down right away.
+func WithDrainDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.drainDelay = delay
+	}
+}
+
+// WithLivenessCheck registers a health check run by both the /healthz and the /readyz endpoints.
+// A failing liveness check means the server must be restarted, so it must not depend on external services.
+// The check fails when it does not finish before the timeout, which defaults to 5s when not positive.
+func WithLivenessCheck(name string, timeout time.Duration, check HealthCheckFunc) Option {
+	return func(s *Server) {
+		s.health.register(&healthCheck{name: name, check: check, timeout: timeout, liveness: true})
+	}
+}
+
+// WithReadinessCheck registers a health check run by the /readyz endpoint, telling whether the server is able
+// to handle requests, like whether its database is reachable.
+// The check fails when it does not finish before the timeout, which defaults to 5s when not positive.
+func WithReadinessCheck(name string, timeout time.Duration, check HealthCheckFunc) Option {
+	return func(s *Server) {
+		s.health.register(&healthCheck{name: name, check: check, timeout: timeout})
+	}
+}
+
+// WithTLS serves TLS with the certificate and key at the given PEM files, which are reloaded as they change on disk.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tls.certFile = certFile
+		s.tls.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig serves TLS with the given config, used as the base of the one built from the certificate files.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.tls.base = cfg
+	}
+}
+
+// WithClientCA requires clients to present a certificate signed by one of the CAs at the given PEM file (mTLS),
+// which is reloaded as it changes on disk. See [ClientIdentityFromContext] to identify the clients.
+func WithClientCA(caFile string) Option {
+	return func(s *Server) {
+		s.tls.clientCAFile = caFile
+	}
+}
+
+// WithClientAuth sets the policy for client certificates, like [tls.VerifyClientCertIfGiven] to accept clients
+// without a certificate. Defaults to [tls.RequireAndVerifyClientCert] when a client CA is configured.
+func WithClientAuth(auth tls.ClientAuthType) Option {
+	return func(s *Server) {
+		s.tls.clientAuth = &auth
+	}
+}
+
+// WithReadTimeout sets the maximum duration for reading an entire request, including its body.
+// Defaults to no timeout.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of a response, counted from the end of
+// the request headers. Defaults to no timeout.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.WriteTimeout = timeout
+	}
+}
+
+// WithIdleTimeout sets for how long keep-alive connections wait for the next request.
+// Defaults to the read timeout, or to no timeout when both are not set.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.IdleTimeout = timeout
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of the request headers, including the request line.
+// Defaults to [http.DefaultMaxHeaderBytes].
+func WithMaxHeaderBytes(size int) Option {
+	return func(s *Server) {
+		s.server.MaxHeaderBytes = size
+	}
+}
+
+// WithListener serves the requests accepted by the given listener, ignoring the port of the server.
+// Listening on port 0 lets tests run on a free port, read back through [Server.Addr].
+func WithListener(listener net.Listener) Option {
+	return func(s *Server) {
+		s.listener = listener
+	}
+}
+
+// WithPrependedMiddlewares adds global middlewares wrapping the default ones, being the first to handle requests.
+func WithPrependedMiddlewares(mws ...mux.MiddlewareFunc) Option {
+	return func(s *Server) {
+		s.prepended = append(s.prepended, mws...)
+	}
+}
+
+// WithMiddlewares adds global middlewares wrapped by the default ones, being the last to run before the routes.
+func WithMiddlewares(mws ...mux.MiddlewareFunc) Option {
+	return func(s *Server) {
+		s.appended = append(s.appended, mws...)
+	}
+}
+
+// WithDefaultMiddlewares replaces the default middlewares, which are middlewares.CorrelationID,
+// middlewares.Telemetry and middlewares.Recover. Calling it without middlewares disables them.
+func WithDefaultMiddlewares(mws ...mux.MiddlewareFunc) Option {
+	return func(s *Server) {
+		s.defaults = mws
+	}
+}
+
+// WithValidator sets the validator of the requests decoded by the handlers built by [Handle].
+// Requests are not validated when no validator is set.
+func WithValidator(validator Validator) Option {
+	return func(s *Server) {
+		s.validator = validator
+	}
+}
+
+// WithOpenAPI serves the OpenAPI document of the routes of the server at /openapi.json, see [OpenAPI].
+func WithOpenAPI(info openapi.Info) Option {
+	return func(s *Server) {
+		s.openAPI = &info
+	}
+}
@@ -0,0 +1,175 @@
+package httpserver_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lcnascimento/go-kit/o11y/baggage"
+
+	"github.com/lcnascimento/go-kit/http/httpserver"
+)
+
+// trace returns a middleware adding its name to the X-Middlewares response header, flagged with a "+" when the
+// correlation ID of the request has already been set by the default middlewares.
+func trace(name string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traced := name
+			if baggage.CorrelationID(r.Context()) != "" {
+				traced += "+"
+			}
+
+			w.Header().Add("X-Middlewares", traced)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func panicRoutes(router *mux.Router) {
+	router.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	router.HandleFunc("/users", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+}
+
+func TestMiddlewares(t *testing.T) {
+	tt := []struct {
+		desc        string
+		opts        []httpserver.Option
+		middlewares []string
+		correlation bool
+	}{
+		{
+			desc:        "should run the default middlewares",
+			correlation: true,
+		},
+		{
+			desc: "should run the prepended middlewares before the defaults and the appended ones after them",
+			opts: []httpserver.Option{
+				httpserver.WithMiddlewares(trace("appended")),
+				httpserver.WithPrependedMiddlewares(trace("first"), trace("second")),
+				httpserver.WithMiddlewares(trace("last")),
+			},
+			middlewares: []string{"first", "second", "appended+", "last+"},
+			correlation: true,
+		},
+		{
+			desc: "should disable the default middlewares",
+			opts: []httpserver.Option{
+				httpserver.WithDefaultMiddlewares(),
+				httpserver.WithMiddlewares(trace("appended")),
+			},
+			middlewares: []string{"appended"},
+		},
+		{
+			desc: "should replace the default middlewares",
+			opts: []httpserver.Option{
+				httpserver.WithDefaultMiddlewares(trace("default")),
+				httpserver.WithPrependedMiddlewares(trace("prepended")),
+			},
+			middlewares: []string{"prepended", "default"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, url := startServer(t, panicRoutes, tc.opts...)
+
+			res, err := http.Get(url + "/users") //nolint:noctx // tests do not need a context.
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			assert.Equal(t, tc.middlewares, res.Header.Values("X-Middlewares"))
+			assert.Equal(t, tc.correlation, res.Header.Get("X-Correlation-Key") != "")
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	_, url := startServer(t, panicRoutes)
+
+	status, _ := get(t, url+"/panic")
+	assert.Equal(t, http.StatusInternalServerError, status, "should recover panics by default")
+
+	_, url = startServer(t, panicRoutes, httpserver.WithDefaultMiddlewares())
+
+	_, err := http.Get(url + "/panic") //nolint:noctx // tests do not need a context.
+	assert.Error(t, err, "should not recover panics without the default middlewares")
+}
+
+func TestLimits(t *testing.T) {
+	sleepy := func(router *mux.Router) {
+		router.HandleFunc("/sleep", func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+
+	t.Run("should reject headers over the limit", func(t *testing.T) {
+		_, url := startServer(t, sleepy, httpserver.WithMaxHeaderBytes(1024))
+
+		request, err := http.NewRequest(http.MethodGet, url+"/sleep", nil) //nolint:noctx // tests do not need a context.
+		require.NoError(t, err)
+
+		// the server reads up to 4KB over the limit before rejecting the headers.
+		request.Header.Set("X-Padding", strings.Repeat("a", 8<<10))
+
+		res, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, res.StatusCode)
+	})
+
+	t.Run("should abort responses over the write timeout", func(t *testing.T) {
+		_, url := startServer(t, sleepy, httpserver.WithWriteTimeout(20*time.Millisecond))
+
+		_, err := http.Get(url + "/sleep") //nolint:noctx // tests do not need a context.
+		assert.Error(t, err)
+	})
+
+	t.Run("should abort requests over the read timeout", func(t *testing.T) {
+		server, _ := startServer(t, func(router *mux.Router) {
+			router.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+				if _, err := io.ReadAll(r.Body); err != nil {
+					w.WriteHeader(http.StatusRequestTimeout)
+				}
+			})
+		}, httpserver.WithReadTimeout(50*time.Millisecond))
+
+		conn, err := net.Dial("tcp", server.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// the body is announced but never sent.
+		_, err = conn.Write([]byte("POST /echo HTTP/1.1\r\nHost: localhost\r\nContent-Length: 10\r\n\r\n"))
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusRequestTimeout, res.StatusCode)
+	})
+}
+
+func TestListener(t *testing.T) {
+	assert.Nil(t, httpserver.NewServer().Addr(), "should not have an address before listening")
+
+	server, url := startServer(t, nil)
+
+	assert.Equal(t, url, "http://"+server.Addr().String())
+
+	status, _ := get(t, "http://"+server.Addr().String()+"/healthz")
+	assert.Equal(t, http.StatusOK, status, "should serve on the given listener")
+}
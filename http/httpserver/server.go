@@ -3,7 +3,9 @@ package httpserver
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -11,22 +13,53 @@ import (
 	"github.com/lcnascimento/go-kit/env"
 
 	"github.com/lcnascimento/go-kit/http/httpserver/middlewares"
+	"github.com/lcnascimento/go-kit/http/httpserver/openapi"
 )
 
-const defaultReadHeaderTimeout = 5 * time.Second
+const (
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultDrainDelay        = 5 * time.Second
+)
 
 var port = env.Get("PORT", env.WithDefaultValue(3000))
 
 type Server struct {
-	server *http.Server
+	server     *http.Server
+	health     *health
+	drainDelay time.Duration
+	tls        tlsConfig
+	validator  Validator
+	openAPI    *openapi.Info
+
+	prepended []mux.MiddlewareFunc
+	defaults  []mux.MiddlewareFunc
+	appended  []mux.MiddlewareFunc
+
+	mu       sync.Mutex
+	listener net.Listener
 }
 
+// NewServer creates a new HTTP server, listening on the port given by the PORT env var (3000 by default).
+//
+// The server serves the /healthz (liveness) and /readyz (readiness) endpoints, which run the health checks
+// registered through [WithLivenessCheck] and [WithReadinessCheck]. Their results are served without the errors of
+// failing checks, which are logged instead, and the results of the last probes are reported as metrics until
+// [Server.Shutdown]. The drain delay applied on [Server.Shutdown] defaults to the HTTP_SERVER_DRAIN_DELAY env var,
+// or to 5s when it is not set.
+//
+// The server serves TLS when a certificate is configured, either through [WithTLS], [WithTLSConfig] or the
+// HTTP_SERVER_TLS_CERT_FILE and HTTP_SERVER_TLS_KEY_FILE env vars. Client certificates are verified against
+// the CA given by [WithClientCA] or by the HTTP_SERVER_TLS_CLIENT_CA_FILE env var.
 func NewServer(opts ...Option) *Server {
 	svr := &Server{
 		server: &http.Server{
 			Addr:              fmt.Sprintf(":%d", port),
 			ReadHeaderTimeout: defaultReadHeaderTimeout,
 		},
+		health:     newHealth(),
+		drainDelay: env.Get("HTTP_SERVER_DRAIN_DELAY", env.WithDefaultValue(defaultDrainDelay)),
+		tls:        defaultTLSConfig(),
+		defaults:   []mux.MiddlewareFunc{middlewares.CorrelationID, middlewares.Telemetry, middlewares.Recover},
 	}
 
 	for _, opt := range opts {
@@ -36,37 +69,119 @@ func NewServer(opts ...Option) *Server {
 	return svr
 }
 
+// Start registers the routes of the server through cb and serves them, blocking until the server is shut down.
+// The routes are wrapped by the prepended middlewares, then by the default ones and lastly by the appended ones.
 func (s *Server) Start(cb func(router *mux.Router) error) error {
 	router := mux.NewRouter()
 
 	router.StrictSlash(true)
 
-	router.Use(middlewares.CorrelationID)
-	router.Use(middlewares.Telemetry)
-	router.Use(middlewares.Recover)
+	router.Use(s.prepended...)
+	router.Use(s.defaults...)
+	router.Use(s.appended...)
 
 	if err := cb(router); err != nil {
 		return err
 	}
 
-	s.server.Handler = router
+	if s.openAPI != nil {
+		if err := serveOpenAPI(router, *s.openAPI); err != nil {
+			return s.onError(err)
+		}
+	}
+
+	s.server.Handler = s.health.handler(withClientIdentity(withValidator(s.validator, router)))
 
-	s.onStart(port)
+	tlsEnabled := s.tls.enabled()
+	if tlsEnabled {
+		tlsConfig, err := s.tls.build()
+		if err != nil {
+			return s.onError(err)
+		}
+
+		s.server.TLSConfig = tlsConfig
+	}
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	listener, err := s.listen()
+	if err != nil {
+		return s.onError(err)
+	}
+
+	s.health.ready.Store(true)
+	s.onStart(listener.Addr(), tlsEnabled)
+
+	if tlsEnabled {
+		err = s.server.ServeTLS(listener, "", "")
+	} else {
+		err = s.server.Serve(listener)
+	}
+
+	if err != nil && err != http.ErrServerClosed {
 		return s.onError(err)
 	}
 
 	return nil
 }
 
+// Addr returns the address the server listens on, or nil when it is not listening yet.
+// It allows reading back the port bound when listening on port 0.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// listen returns the listener given by [WithListener], or listens on the address of the server.
+func (s *Server) listen() (net.Listener, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return s.listener, nil
+	}
+
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.listener = listener
+
+	return listener, nil
+}
+
+// Shutdown gracefully shuts down the server. Readiness starts failing right away, so that load balancers stop
+// sending traffic to the server, which keeps serving requests during the drain delay before shutting down.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.onShutdown()
 
+	s.health.ready.Store(false)
+
+	if s.drainDelay > 0 {
+		s.onDrain(s.drainDelay)
+
+		timer := time.NewTimer(s.drainDelay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
 	err := s.server.Shutdown(ctx)
 	if err != nil {
 		s.onError(err)
 	}
 
+	if closeErr := s.health.close(); closeErr != nil && err == nil {
+		err = s.onError(closeErr)
+	}
+
 	return err
 }
@@ -0,0 +1,252 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpserver"
+)
+
+var metrics = sdkMetric.NewManualReader()
+
+func TestMain(m *testing.M) {
+	otel.SetMeterProvider(sdkMetric.NewMeterProvider(sdkMetric.WithReader(metrics)))
+
+	os.Exit(m.Run())
+}
+
+// startServer starts a server on a free port with the given routes, returning it along with its base URL.
+// The server is shut down when the test finishes.
+func startServer(t *testing.T, routes func(router *mux.Router), opts ...httpserver.Option) (*httpserver.Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := httpserver.NewServer(append([]httpserver.Option{
+		httpserver.WithListener(listener),
+		httpserver.WithDrainDelay(0),
+	}, opts...)...)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Start(func(router *mux.Router) error {
+			if routes != nil {
+				routes(router)
+			}
+
+			return nil
+		})
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+		assert.NoError(t, <-done)
+	})
+
+	return server, "http://" + listener.Addr().String()
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	t.Helper()
+
+	res, err := http.Get(url) //nolint:noctx // tests do not need a context.
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var body json.RawMessage
+	_ = json.NewDecoder(res.Body).Decode(&body)
+
+	return res.StatusCode, body
+}
+
+func failing(context.Context) error { return errors.New("database password expired") }
+
+func healthy(context.Context) error { return nil }
+
+func slow(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	tt := []struct {
+		desc      string
+		opts      []httpserver.Option
+		path      string
+		status    int
+		checks    map[string]httpserver.HealthStatus
+		aggregate httpserver.HealthStatus
+	}{
+		{
+			desc:      "should be healthy without checks",
+			path:      "/healthz",
+			status:    http.StatusOK,
+			aggregate: httpserver.HealthStatusOK,
+		},
+		{
+			desc:      "should be ready without checks",
+			path:      "/readyz",
+			status:    http.StatusOK,
+			aggregate: httpserver.HealthStatusOK,
+		},
+		{
+			desc: "should run only the liveness checks on healthz",
+			opts: []httpserver.Option{
+				httpserver.WithLivenessCheck("goroutines", 0, healthy),
+				httpserver.WithReadinessCheck("database", 0, failing),
+			},
+			path:      "/healthz",
+			status:    http.StatusOK,
+			checks:    map[string]httpserver.HealthStatus{"goroutines": httpserver.HealthStatusOK},
+			aggregate: httpserver.HealthStatusOK,
+		},
+		{
+			desc: "should run every check on readyz",
+			opts: []httpserver.Option{
+				httpserver.WithLivenessCheck("goroutines", 0, healthy),
+				httpserver.WithReadinessCheck("database", 0, failing),
+			},
+			path:   "/readyz",
+			status: http.StatusServiceUnavailable,
+			checks: map[string]httpserver.HealthStatus{
+				"goroutines": httpserver.HealthStatusOK,
+				"database":   httpserver.HealthStatusFailing,
+			},
+			aggregate: httpserver.HealthStatusFailing,
+		},
+		{
+			desc:      "should fail checks that time out",
+			opts:      []httpserver.Option{httpserver.WithLivenessCheck("deadlock", 20*time.Millisecond, slow)},
+			path:      "/healthz",
+			status:    http.StatusServiceUnavailable,
+			checks:    map[string]httpserver.HealthStatus{"deadlock": httpserver.HealthStatusFailing},
+			aggregate: httpserver.HealthStatusFailing,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, url := startServer(t, nil, tc.opts...)
+
+			status, body := get(t, url+tc.path)
+			assert.Equal(t, tc.status, status)
+			assert.NotContains(t, string(body), "password", "should not serve the errors of failing checks")
+
+			var report httpserver.HealthReport
+			require.NoError(t, json.Unmarshal(body, &report))
+
+			assert.Equal(t, tc.aggregate, report.Status)
+
+			checks := make(map[string]httpserver.HealthStatus)
+			for name, result := range report.Checks {
+				checks[name] = result.Status
+			}
+
+			assert.Equal(t, len(tc.checks), len(checks))
+			for name, status := range tc.checks {
+				assert.Equal(t, status, checks[name], name)
+			}
+		})
+	}
+}
+
+func TestShutdownDrain(t *testing.T) {
+	server, url := startServer(t, func(router *mux.Router) {
+		router.HandleFunc("/users", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}, httpserver.WithDrainDelay(200*time.Millisecond))
+
+	status, _ := get(t, url+"/readyz")
+	require.Equal(t, http.StatusOK, status)
+
+	done := make(chan error, 1)
+	go func() { done <- server.Shutdown(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		status, _ := get(t, url+"/readyz")
+		return status == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond, "should fail readiness once shutting down")
+
+	status, _ = get(t, url+"/users")
+	assert.Equal(t, http.StatusNoContent, status, "should keep serving requests while draining")
+
+	status, _ = get(t, url+"/healthz")
+	assert.Equal(t, http.StatusOK, status, "should stay alive while draining")
+
+	require.NoError(t, <-done)
+
+	_, err := http.Get(url + "/users") //nolint:noctx // tests do not need a context.
+	assert.Error(t, err, "should stop serving once drained")
+}
+
+// healthGauges collects the health gauges of the servers, keyed by the name of the check, the readiness of
+// the server being keyed by an empty name.
+func healthGauges(t *testing.T) map[string][]float64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(context.Background(), &rm))
+
+	gauges := make(map[string][]float64)
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			data, ok := m.Data.(metricdata.Gauge[float64])
+			if !ok {
+				continue
+			}
+
+			for _, dp := range data.DataPoints {
+				name, _ := dp.Attributes.Value("health.check.name")
+
+				switch m.Name {
+				case "http.server.readiness":
+					gauges[""] = append(gauges[""], dp.Value)
+				case "http.server.health_check.status":
+					gauges[name.AsString()] = append(gauges[name.AsString()], dp.Value)
+				}
+			}
+		}
+	}
+
+	return gauges
+}
+
+func TestHealthMetrics(t *testing.T) {
+	var runs atomic.Int32
+
+	server, url := startServer(t, nil, httpserver.WithReadinessCheck("cache", 0, func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+
+	status, _ := get(t, url+"/readyz")
+	require.Equal(t, http.StatusOK, status)
+
+	gauges := healthGauges(t)
+
+	assert.Equal(t, []float64{1}, gauges["cache"])
+	assert.Contains(t, gauges[""], 1.0)
+	assert.Equal(t, int32(1), runs.Load(), "should not run the checks to collect metrics")
+
+	require.NoError(t, server.Shutdown(context.Background()))
+
+	assert.Empty(t, healthGauges(t)["cache"], "should stop reporting once shut down")
+}
@@ -2,25 +2,100 @@ package httpserver
 
 import (
 	"context"
+	"net"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	oMetric "go.opentelemetry.io/otel/metric"
 
 	"github.com/lcnascimento/go-kit/o11y/log"
+	"github.com/lcnascimento/go-kit/o11y/metric"
 )
 
 var (
 	pkg    = "github.com/lcnascimento/go-kit/http/httpserver"
 	logger = log.MustNewLogger(pkg)
+	meter  = otel.Meter(pkg)
+
+	readinessGauge = metric.MustFloat64ObservableGauge(
+		meter,
+		"http.server.readiness",
+		"Aggregated readiness of the server: 1 for ok and 0 for failing",
+	)
+	healthCheckGauge = metric.MustFloat64ObservableGauge(
+		meter,
+		"http.server.health_check.status",
+		"Result of each health check of the server: 1 for ok and 0 for failing",
+	)
 )
 
-func (s *Server) onStart(port int) {
-	logger.Info(context.Background(), "starting HTTP server", log.Int("port", port))
+func (s *Server) onStart(addr net.Addr, tls bool) {
+	logger.Info(context.Background(), "starting HTTP server", log.String("address", addr.String()), log.Bool("tls", tls))
 }
 
 func (s *Server) onShutdown() {
 	logger.Info(context.Background(), "shutting down HTTP server")
 }
 
+func (s *Server) onDrain(delay time.Duration) {
+	logger.Info(context.Background(), "draining HTTP server", log.String("http.server.drain_delay", delay.String()))
+}
+
 func (s *Server) onError(err error) error {
 	logger.ErrorBySeverity(context.Background(), err)
 
 	return err
 }
+
+// onCreate reports the readiness of the server, along with the result of each of its health checks, whenever
+// metrics are collected. The results of the last probes are reported, so that collecting metrics runs no check.
+func (h *health) onCreate() oMetric.Registration {
+	registration, _ := meter.RegisterCallback(func(_ context.Context, o oMetric.Observer) error {
+		status := HealthStatusOK
+		if !h.ready.Load() {
+			status = HealthStatusFailing
+		}
+
+		for _, check := range h.registered(false) {
+			result := check.last.Load()
+			if result == nil {
+				continue
+			}
+
+			if result.Status != HealthStatusOK {
+				status = HealthStatusFailing
+			}
+
+			attr := attribute.String("health.check.name", check.name)
+			o.ObserveFloat64(healthCheckGauge, healthValue(result.Status), oMetric.WithAttributes(attr))
+		}
+
+		o.ObserveFloat64(readinessGauge, healthValue(status))
+
+		return nil
+	}, readinessGauge, healthCheckGauge)
+
+	return registration
+}
+
+func (c *healthCheck) onFailure(ctx context.Context, err error) {
+	logger.Warn(ctx, "health check failed", log.String("health.check.name", c.name), log.String("error.message", err.Error()))
+}
+
+func healthValue(status HealthStatus) float64 {
+	if status == HealthStatusOK {
+		return 1
+	}
+
+	return 0
+}
+
+func (r *certReloader) onReload() {
+	logger.Info(context.Background(), "TLS certificates reloaded", log.String("file", r.certFile))
+}
+
+func (r *certReloader) onReloadError(err error) {
+	logger.Warn(context.Background(), "could not reload TLS certificates", log.String("error.message", err.Error()))
+}
@@ -0,0 +1,234 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lcnascimento/go-kit/env"
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+// tlsReloadInterval is the minimum interval between checks for changes of the certificate files.
+const tlsReloadInterval = time.Second
+
+type tlsConfig struct {
+	base         *tls.Config
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   *tls.ClientAuthType
+}
+
+func defaultTLSConfig() tlsConfig {
+	return tlsConfig{
+		certFile:     env.Get[string]("HTTP_SERVER_TLS_CERT_FILE"),
+		keyFile:      env.Get[string]("HTTP_SERVER_TLS_KEY_FILE"),
+		clientCAFile: env.Get[string]("HTTP_SERVER_TLS_CLIENT_CA_FILE"),
+	}
+}
+
+// enabled reports whether the server serves TLS. Client certificate settings enable it too, so that a missing
+// server certificate fails the start instead of silently serving plain HTTP.
+func (c tlsConfig) enabled() bool {
+	return c.base != nil || c.certFile != "" || c.keyFile != "" || c.clientCAFile != "" || c.clientAuth != nil
+}
+
+// build builds the TLS configuration of the server. When certificate files are configured, they are
+// reloaded as they change on disk, so that rotated certificates are served without restarting the server.
+func (c tlsConfig) build() (*tls.Config, error) {
+	if c.base == nil && c.certFile == "" && c.keyFile == "" {
+		return nil, ErrLoadTLSCredentials.WithCause(errors.New("client certificates require a server certificate"))
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.base != nil {
+		cfg = c.base.Clone()
+	}
+
+	switch {
+	case c.clientAuth != nil:
+		cfg.ClientAuth = *c.clientAuth
+	case c.clientCAFile != "":
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if c.certFile == "" && c.keyFile == "" && c.clientCAFile == "" {
+		return cfg, nil
+	}
+
+	// The config returned for each client replaces the server one, so it must advertise the protocols served.
+	if len(cfg.NextProtos) == 0 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	}
+
+	reloader := &certReloader{
+		base:         cfg,
+		certFile:     c.certFile,
+		keyFile:      c.keyFile,
+		clientCAFile: c.clientCAFile,
+	}
+
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:         cfg.MinVersion,
+		GetConfigForClient: reloader.configForClient,
+	}, nil
+}
+
+// certReloader serves the certificates loaded from files, reloading them when the files change.
+type certReloader struct {
+	base         *tls.Config
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+func (r *certReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < tlsReloadInterval {
+		return r.config, nil
+	}
+
+	r.checkedAt = time.Now()
+
+	if !r.changed() {
+		return r.config, nil
+	}
+
+	// The previous certificates keep being served until the new ones are valid, as files may be
+	// rotated one at a time.
+	if err := r.reload(); err != nil {
+		r.onReloadError(err)
+		return r.config, nil
+	}
+
+	r.onReload()
+
+	return r.config, nil
+}
+
+func (r *certReloader) files() []string {
+	files := make([]string, 0, 3)
+
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+func (r *certReloader) changed() bool {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *certReloader) reload() error {
+	modTimes := make(map[string]time.Time, 3)
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return ErrLoadTLSCredentials.WithCause(err).WithAttribute("file", file)
+		}
+
+		modTimes[file] = info.ModTime()
+	}
+
+	cfg := r.base.Clone()
+
+	if r.certFile != "" || r.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if r.clientCAFile != "" {
+		ca, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return ErrLoadTLSCredentials.WithCause(err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return ErrLoadTLSCredentials.WithAttribute("file", r.clientCAFile)
+		}
+
+		cfg.ClientCAs = pool
+	}
+
+	r.config = cfg
+	r.modTimes = modTimes
+
+	return nil
+}
+
+// ClientIdentity is the identity of a client authenticated by a verified TLS certificate (mTLS).
+type ClientIdentity struct {
+	CommonName     string
+	DNSNames       []string
+	URIs           []string
+	EmailAddresses []string
+
+	// Certificate is the verified certificate presented by the client.
+	Certificate *x509.Certificate
+}
+
+type clientIdentityKey struct{}
+
+// ClientIdentityFromContext retrieves the identity of the client whose request is being handled.
+// It is only found when the server verifies client certificates, see [WithClientCA].
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityKey{}).(*ClientIdentity)
+	return identity, ok
+}
+
+// withClientIdentity exposes the identity of the client to the handlers, when its certificate is verified.
+func withClientIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cert := r.TLS.VerifiedChains[0][0]
+
+		identity := &ClientIdentity{
+			CommonName:     cert.Subject.CommonName,
+			DNSNames:       cert.DNSNames,
+			EmailAddresses: cert.EmailAddresses,
+			Certificate:    cert,
+		}
+
+		for _, uri := range cert.URIs {
+			identity.URIs = append(identity.URIs, uri.String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIdentityKey{}, identity)))
+	})
+}
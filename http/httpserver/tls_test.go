@@ -0,0 +1,321 @@
+package httpserver_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	URL "net/url"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/http/httpserver"
+)
+
+// authority is a test CA, issuing certificates into PEM files of its own directory.
+type authority struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	file string
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+
+	a := &authority{dir: t.TempDir(), pool: x509.NewCertPool()}
+
+	a.cert, a.key = a.sign(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test ca"},
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+
+	a.pool.AddCert(a.cert)
+	a.file = a.write(t, "ca.pem", "CERTIFICATE", a.cert.Raw)
+
+	return a
+}
+
+// issue issues a certificate for the given common name, writing it and its key into <name>.pem and <name>-key.pem.
+func (a *authority) issue(t *testing.T, name, commonName string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+
+	uri, err := URL.Parse("spiffe://test/" + commonName)
+	require.NoError(t, err)
+
+	cert, key := a.sign(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		URIs:        []*URL.URL{uri},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{usage},
+	}, a.cert, a.key)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return a.write(t, name+".pem", "CERTIFICATE", cert.Raw), a.write(t, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func (a *authority) sign(
+	t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func (a *authority) write(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(a.dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+// client returns an HTTP client trusting the authority, presenting the given certificate files when set.
+func (a *authority) client(t *testing.T, certFile, keyFile string) *http.Client {
+	t.Helper()
+
+	cfg := &tls.Config{RootCAs: a.pool, MinVersion: tls.VersionTLS12}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		require.NoError(t, err)
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	// new connections for every request, so that each one goes through the TLS handshake.
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+}
+
+// identityRoutes serve the common name of the client identity, or "anonymous" when there is none.
+func identityRoutes(router *mux.Router) {
+	router.HandleFunc("/whoami", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := httpserver.ClientIdentityFromContext(r.Context())
+		if !ok {
+			_, _ = w.Write([]byte("anonymous"))
+			return
+		}
+
+		_, _ = w.Write([]byte(identity.CommonName + " " + identity.URIs[0]))
+	})
+}
+
+func whoami(t *testing.T, client *http.Client, server *httpserver.Server) (string, *tls.ConnectionState, error) {
+	t.Helper()
+
+	res, err := client.Get("https://" + server.Addr().String() + "/whoami") //nolint:noctx // tests do not need a context.
+	if err != nil {
+		return "", nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return string(body), res.TLS, nil
+}
+
+func TestTLS(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, serverKey := ca.issue(t, "server", "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "client", "billing", x509.ExtKeyUsageClientAuth)
+
+	keyPair, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	tt := []struct {
+		desc       string
+		opts       []httpserver.Option
+		clientCert bool
+		expected   string
+		fail       bool
+	}{
+		{
+			desc:     "should serve tls from certificate files",
+			opts:     []httpserver.Option{httpserver.WithTLS(serverCert, serverKey)},
+			expected: "anonymous",
+		},
+		{
+			desc: "should serve tls from a tls config",
+			opts: []httpserver.Option{
+				httpserver.WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{keyPair}, MinVersion: tls.VersionTLS12}),
+			},
+			expected: "anonymous",
+		},
+		{
+			desc:       "should expose the identity of verified clients",
+			opts:       []httpserver.Option{httpserver.WithTLS(serverCert, serverKey), httpserver.WithClientCA(ca.file)},
+			clientCert: true,
+			expected:   "billing spiffe://test/billing",
+		},
+		{
+			desc: "should reject clients without certificates",
+			opts: []httpserver.Option{httpserver.WithTLS(serverCert, serverKey), httpserver.WithClientCA(ca.file)},
+			fail: true,
+		},
+		{
+			desc: "should accept clients without certificates when they are optional",
+			opts: []httpserver.Option{
+				httpserver.WithTLS(serverCert, serverKey),
+				httpserver.WithClientCA(ca.file),
+				httpserver.WithClientAuth(tls.VerifyClientCertIfGiven),
+			},
+			expected: "anonymous",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			server, _ := startServer(t, identityRoutes, tc.opts...)
+
+			client := ca.client(t, "", "")
+			if tc.clientCert {
+				client = ca.client(t, clientCert, clientKey)
+			}
+
+			got, state, err := whoami(t, client, server)
+			if tc.fail {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+			assert.NotNil(t, state)
+		})
+	}
+}
+
+func TestTLSFromEnv(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, serverKey := ca.issue(t, "server", "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "client", "billing", x509.ExtKeyUsageClientAuth)
+
+	t.Setenv("HTTP_SERVER_TLS_CERT_FILE", serverCert)
+	t.Setenv("HTTP_SERVER_TLS_KEY_FILE", serverKey)
+	t.Setenv("HTTP_SERVER_TLS_CLIENT_CA_FILE", ca.file)
+
+	server, _ := startServer(t, identityRoutes)
+
+	got, _, err := whoami(t, ca.client(t, clientCert, clientKey), server)
+	require.NoError(t, err)
+
+	assert.Equal(t, "billing spiffe://test/billing", got)
+}
+
+func TestTLSReload(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, serverKey := ca.issue(t, "server", "server", x509.ExtKeyUsageServerAuth)
+
+	server, _ := startServer(t, identityRoutes, httpserver.WithTLS(serverCert, serverKey))
+	client := ca.client(t, "", "")
+
+	_, state, err := whoami(t, client, server)
+	require.NoError(t, err)
+
+	served := state.PeerCertificates[0].SerialNumber
+
+	ca.issue(t, "server", "server", x509.ExtKeyUsageServerAuth)
+
+	// the modification time of the files may not change within the precision of the file system.
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(serverCert, future, future))
+	require.NoError(t, os.Chtimes(serverKey, future, future))
+
+	require.Eventually(t, func() bool {
+		_, state, err := whoami(t, client, server)
+		return err == nil && state.PeerCertificates[0].SerialNumber.Cmp(served) != 0
+	}, 5*time.Second, 100*time.Millisecond, "should serve the rotated certificate")
+}
+
+func TestTLSInvalidCredentials(t *testing.T) {
+	ca := newAuthority(t)
+	serverCert, _ := ca.issue(t, "server", "server", x509.ExtKeyUsageServerAuth)
+
+	tt := []struct {
+		desc string
+		opts []httpserver.Option
+	}{
+		{
+			desc: "should fail on missing certificate files",
+			opts: []httpserver.Option{httpserver.WithTLS(filepath.Join(t.TempDir(), "missing.pem"), serverCert)},
+		},
+		{
+			desc: "should fail on invalid key pairs",
+			opts: []httpserver.Option{httpserver.WithTLS(serverCert, ca.file)},
+		},
+		{
+			desc: "should fail on missing client cas",
+			opts: []httpserver.Option{
+				httpserver.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+				httpserver.WithClientCA(filepath.Join(t.TempDir(), "missing.pem")),
+			},
+		},
+		{
+			desc: "should fail on client cas without a server certificate",
+			opts: []httpserver.Option{httpserver.WithClientCA(ca.file)},
+		},
+		{
+			desc: "should fail on client auth policies without a server certificate",
+			opts: []httpserver.Option{httpserver.WithClientAuth(tls.VerifyClientCertIfGiven)},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			server := httpserver.NewServer(append(tc.opts, httpserver.WithListener(listener), httpserver.WithDrainDelay(0))...)
+
+			err = server.Start(func(*mux.Router) error { return nil })
+			assert.Equal(t, errors.Code(httpserver.ErrLoadTLSCredentials), errors.Code(err))
+
+			_ = server.Shutdown(context.Background())
+		})
+	}
+}
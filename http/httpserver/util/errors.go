@@ -11,6 +11,7 @@ import (
 var (
 	ErrParseRequestBody     = errors.New("failed to parse request body").WithCode("ERR_PARSE_REQUEST_BODY").WithKind(errors.KindInvalidInput)
 	ErrMissingCorrelationID = errors.New("missing correlation id parameter").WithCode("ERR_MISSING_CORRELATION_ID").WithKind(errors.KindInvalidInput)
+	ErrBindRequestParam     = errors.New("failed to bind request parameter").WithCode("ERR_BIND_REQUEST_PARAM").WithKind(errors.KindInvalidInput)
 
 	logger = log.MustNewLogger("github.com/lcnascimento/go-kit/http/httpserver/util")
 )
@@ -47,13 +48,24 @@ func WriteError(ctx context.Context, rw http.ResponseWriter, err error) {
 	kind := errors.Kind(err)
 	status := kindToHTTPStatusCode(kind)
 
-	if errors.Is(err, ErrParseRequestBody) {
+	if errors.Is(err, ErrParseRequestBody) || errors.Is(err, ErrBindRequestParam) {
 		logger.Error(ctx, err)
 	}
 
 	WriteResponse(rw, status, NewAPIError(err))
 }
 
+// ErrorKindsByStatusCode groups the kinds of error by the status code they are written with by [WriteError].
+func ErrorKindsByStatusCode() map[int][]errors.KindType {
+	out := make(map[int][]errors.KindType)
+	for _, kind := range errors.Kinds() {
+		status := kindToHTTPStatusCode(kind)
+		out[status] = append(out[status], kind)
+	}
+
+	return out
+}
+
 func kindToHTTPStatusCode(kind errors.KindType) int {
 	switch kind {
 	case errors.KindInvalidInput:
@@ -0,0 +1,27 @@
+package util_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lcnascimento/go-kit/errors"
+
+	"github.com/lcnascimento/go-kit/http/httpserver/util"
+)
+
+func TestErrorKindsByStatusCode(t *testing.T) {
+	kindsByStatus := util.ErrorKindsByStatusCode()
+
+	var kinds []errors.KindType
+	for _, statusKinds := range kindsByStatus {
+		kinds = append(kinds, statusKinds...)
+	}
+
+	assert.ElementsMatch(t, errors.Kinds(), kinds, "should group every kind once")
+
+	assert.Equal(t, []errors.KindType{errors.KindInvalidInput}, kindsByStatus[http.StatusBadRequest])
+	assert.Equal(t, []errors.KindType{errors.KindNotFound}, kindsByStatus[http.StatusNotFound])
+	assert.Contains(t, kindsByStatus[http.StatusInternalServerError], errors.KindUnknown)
+}
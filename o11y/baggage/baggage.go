@@ -74,3 +74,14 @@ func ContextWithMembers(ctx context.Context, members ...Member) context.Context
 func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
 	return ContextWithMembers(ctx, NewMember(MemberKeyCorrelationID, correlationID))
 }
+
+// CorrelationID retrieves the correlation ID from the baggage. It returns an empty string when it is not set.
+func CorrelationID(ctx context.Context) string {
+	value := oBaggage.FromContext(ctx).Member(MemberKeyCorrelationID).Value()
+
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		return unescaped
+	}
+
+	return value
+}
@@ -24,3 +24,11 @@ func TestContextWithCorrelationID(t *testing.T) {
 
 	require.Equal(t, baggage.FromContext(ctx).Member(baggage.MemberKeyCorrelationID).Value(), cID)
 }
+
+func TestCorrelationID(t *testing.T) {
+	cID := uuid.New().String()
+	ctx := baggage.ContextWithCorrelationID(context.Background(), cID)
+
+	require.Equal(t, cID, baggage.CorrelationID(ctx))
+	require.Empty(t, baggage.CorrelationID(context.Background()))
+}
@@ -99,6 +99,7 @@ func fakeError(msg string, code errors.CodeType) error {
 	return errors.New("%s", msg).
 		WithAttribute("key1", "value1").
 		WithAttribute("key2", "value2").
+		WithAttrs(errors.IntAttr("attempt", 3), errors.DurationAttr("elapsed", time.Second)).
 		WithCode(code).
 		WithKind(errors.KindResourceExhausted).
 		WithCause(errors.New("nested error"))
@@ -2,7 +2,9 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/lcnascimento/go-kit/errors"
 )
@@ -163,8 +165,8 @@ func errorAttr(err error) Attr {
 		Bool("retryable", errors.IsRetryable(err)),
 	}
 
-	for key, value := range errors.Attributes(err) {
-		attrs = append(attrs, String(key, value))
+	for _, attr := range errors.Attrs(err) {
+		attrs = append(attrs, attr)
 	}
 
 	reasons := errors.Reasons(err)
@@ -172,5 +174,23 @@ func errorAttr(err error) Attr {
 		attrs = append(attrs, Any("reasons", reasons))
 	}
 
+	if stack := stackTrace(err); stack != "" {
+		attrs = append(attrs, String("stacktrace", stack))
+	}
+
 	return Group("error", attrs...)
 }
+
+func stackTrace(err error) string {
+	frames := errors.StackTrace(err)
+	if len(frames) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, frame := range frames {
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+
+	return sb.String()
+}
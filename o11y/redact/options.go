@@ -0,0 +1,71 @@
+package redact
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type Option func(*Redactor)
+
+// WithMask sets the value that replaces redacted data. Defaults to [DefaultMask].
+func WithMask(mask string) Option {
+	return func(r *Redactor) {
+		r.mask = mask
+	}
+}
+
+// WithMaxBodySize sets the maximum size, in bytes, of redacted bodies. Larger bodies are truncated.
+// Defaults to [DefaultMaxBodySize]. Zero disables truncation.
+func WithMaxBodySize(size int) Option {
+	return func(r *Redactor) {
+		r.maxBodySize = size
+	}
+}
+
+// WithHeaders adds header names, case insensitive, whose values are always masked.
+func WithHeaders(names ...string) Option {
+	return func(r *Redactor) {
+		for _, name := range names {
+			r.headers[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithJSONKeys adds JSON keys, case insensitive, whose values are masked at any depth of a JSON body,
+// and in form-urlencoded bodies.
+func WithJSONKeys(keys ...string) Option {
+	return func(r *Redactor) {
+		for _, key := range keys {
+			r.jsonKeys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// WithJSONPaths adds dot separated JSON paths whose values are masked, e.g. "user.document".
+// A "*" segment matches any key, e.g. "*.document". Array items are matched by the path of their array,
+// so "cards.number" masks the number of every entry of cards.
+func WithJSONPaths(paths ...string) Option {
+	return func(r *Redactor) {
+		for _, path := range paths {
+			r.jsonPaths = append(r.jsonPaths, strings.Split(path, "."))
+		}
+	}
+}
+
+// WithQueryParams adds query param names, case insensitive, whose values are masked in URLs
+// and in form-urlencoded bodies.
+func WithQueryParams(names ...string) Option {
+	return func(r *Redactor) {
+		for _, name := range names {
+			r.queryParams[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithPatterns adds regular expressions whose matches are masked in bodies and header values.
+func WithPatterns(patterns ...*regexp.Regexp) Option {
+	return func(r *Redactor) {
+		r.patterns = append(r.patterns, patterns...)
+	}
+}
@@ -0,0 +1,289 @@
+// Package redact masks sensitive data, like credentials and PII, before it reaches logs, spans and errors.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultMask is the value that replaces redacted data.
+	DefaultMask = "[REDACTED]"
+
+	// DefaultMaxBodySize is the default maximum size, in bytes, of a redacted body.
+	DefaultMaxBodySize = 4 * 1024
+
+	truncatedSuffix = "...[TRUNCATED]"
+
+	// urlCredentials replaces the credentials of redacted URLs, as recommended by the OpenTelemetry conventions.
+	urlCredentials = "REDACTED"
+)
+
+var (
+	defaultHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+	}
+
+	defaultJSONKeys = []string{
+		"password",
+		"secret",
+		"token",
+		"access_token",
+		"refresh_token",
+		"client_secret",
+		"api_key",
+	}
+
+	defaultQueryParams = []string{
+		"AWSAccessKeyId",
+		"Signature",
+		"sig",
+		"X-Goog-Signature",
+		"access_token",
+		"api_key",
+		"client_secret",
+		"token",
+	}
+)
+
+// Redactor masks sensitive data from bodies and headers. It is safe for concurrent use.
+type Redactor struct {
+	mask        string
+	maxBodySize int
+	headers     map[string]bool
+	jsonKeys    map[string]bool
+	jsonPaths   [][]string
+	queryParams map[string]bool
+	patterns    []*regexp.Regexp
+}
+
+// New creates a new Redactor with the given options.
+// Without options, it masks nothing but still truncates bodies to [DefaultMaxBodySize]. See [Default].
+func New(opts ...Option) *Redactor {
+	r := &Redactor{
+		mask:        DefaultMask,
+		maxBodySize: DefaultMaxBodySize,
+		headers:     make(map[string]bool),
+		jsonKeys:    make(map[string]bool),
+		queryParams: make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Default creates a new Redactor that masks well known credential headers, like Authorization and Cookie,
+// JSON keys, like password and token, at any depth, and query params, like signatures and access tokens.
+// More options may be given to extend it.
+func Default(opts ...Option) *Redactor {
+	defaults := []Option{
+		WithHeaders(defaultHeaders...),
+		WithJSONKeys(defaultJSONKeys...),
+		WithQueryParams(defaultQueryParams...),
+	}
+
+	opts = append(defaults, opts...)
+	return New(opts...)
+}
+
+// Body redacts the given body. JSON bodies get their denied keys and paths masked, while form-urlencoded bodies
+// get the values of their denied keys and query params masked. Regex masks are applied to every body, which is
+// then truncated to the max body size.
+func (r *Redactor) Body(body []byte) string {
+	switch {
+	case json.Valid(body):
+		if len(r.jsonKeys) > 0 || len(r.jsonPaths) > 0 {
+			body = r.redactJSON(body)
+		}
+	case len(r.jsonKeys) > 0 || len(r.queryParams) > 0:
+		body = []byte(r.redactForm(string(body)))
+	}
+
+	return r.truncate(r.applyPatterns(string(body)))
+}
+
+// String applies the regex masks to the given value and truncates it to the max body size.
+func (r *Redactor) String(value string) string {
+	return r.truncate(r.applyPatterns(value))
+}
+
+// Header returns a copy of the given header with the values of denied headers masked.
+func (r *Redactor) Header(header http.Header) http.Header {
+	out := make(http.Header, len(header))
+
+	for name, values := range header {
+		redacted := make([]string, len(values))
+		for i, value := range values {
+			redacted[i] = r.HeaderValue(name, value)
+		}
+
+		out[name] = redacted
+	}
+
+	return out
+}
+
+// HeaderValue redacts the value of the given header.
+func (r *Redactor) HeaderValue(name, value string) string {
+	if r.IsDeniedHeader(name) {
+		return r.mask
+	}
+
+	return r.applyPatterns(value)
+}
+
+// URL returns the given URL as a string, with its credentials and the values of denied query params masked.
+func (r *Redactor) URL(u *url.URL) string {
+	redacted := *u
+
+	if u.User != nil {
+		redacted.User = url.UserPassword(urlCredentials, urlCredentials)
+	}
+
+	if len(r.queryParams) == 0 || u.RawQuery == "" {
+		return redacted.String()
+	}
+
+	query := u.Query()
+	for key := range query {
+		if r.queryParams[strings.ToLower(key)] {
+			query[key] = []string{r.mask}
+		}
+	}
+
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+// IsDeniedHeader reports whether the values of the given header are always masked.
+func (r *Redactor) IsDeniedHeader(name string) bool {
+	return r.headers[http.CanonicalHeaderKey(name)]
+}
+
+func (r *Redactor) applyPatterns(value string) string {
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllString(value, r.mask)
+	}
+
+	return value
+}
+
+func (r *Redactor) truncate(value string) string {
+	if r.maxBodySize <= 0 || len(value) <= r.maxBodySize {
+		return value
+	}
+
+	return strings.ToValidUTF8(value[:r.maxBodySize], "") + truncatedSuffix
+}
+
+func (r *Redactor) redactJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload any
+	if err := decoder.Decode(&payload); err != nil || decoder.More() {
+		return body
+	}
+
+	redacted, err := json.Marshal(r.redactValue(payload, nil))
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// redactForm masks the values of the denied keys and query params of form-urlencoded bodies,
+// keeping the other bodies as they are.
+func (r *Redactor) redactForm(body string) string {
+	if !isForm(body) {
+		return body
+	}
+
+	pairs := strings.Split(body, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			continue
+		}
+
+		if name = strings.ToLower(name); r.jsonKeys[name] || r.queryParams[name] {
+			pairs[i] = key + "=" + r.mask
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// isForm reports whether the body looks like a form-urlencoded one, made of key=value pairs.
+func isForm(body string) bool {
+	if !strings.Contains(body, "=") || strings.ContainsFunc(body, unicode.IsSpace) {
+		return false
+	}
+
+	_, err := url.ParseQuery(body)
+
+	return err == nil
+}
+
+func (r *Redactor) redactValue(value any, path []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			innerPath := append(path[:len(path):len(path)], key)
+
+			if r.jsonKeys[strings.ToLower(key)] || r.isDeniedPath(innerPath) {
+				v[key] = r.mask
+				continue
+			}
+
+			v[key] = r.redactValue(inner, innerPath)
+		}
+	case []any:
+		// array items share the path of the array itself.
+		for i, inner := range v {
+			v[i] = r.redactValue(inner, path)
+		}
+	}
+
+	return value
+}
+
+func (r *Redactor) isDeniedPath(path []string) bool {
+	for _, denied := range r.jsonPaths {
+		if matchPath(denied, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package trace
+
+import (
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+// RecordError records the given error as an exception event of the span.
+// The error Kind, Code, Severity, Retryable flag and typed attributes are attached to the event,
+// keeping the attributes native types.
+func RecordError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	opts = append(opts, trace.WithAttributes(ErrorAttributes(err)...))
+	span.RecordError(err, opts...)
+}
+
+// ErrorAttributes converts the data carried by the given error into span attributes.
+// Nested groups are flattened, using dot separated keys.
+func ErrorAttributes(err error) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("error.code", string(errors.Code(err))),
+		attribute.String("error.kind", string(errors.Kind(err))),
+		attribute.String("error.severity", errors.Severity(err).String()),
+		attribute.Bool("error.retryable", errors.IsRetryable(err)),
+	}
+
+	for _, attr := range errors.Attrs(err) {
+		attrs = append(attrs, keyValues(attr.Key, attr.Value)...)
+	}
+
+	return attrs
+}
+
+//nolint:gosec // OK with overflow
+func keyValues(key string, value slog.Value) []attribute.KeyValue {
+	switch value.Kind() {
+	case slog.KindString:
+		return []attribute.KeyValue{attribute.String(key, value.String())}
+	case slog.KindInt64:
+		return []attribute.KeyValue{attribute.Int64(key, value.Int64())}
+	case slog.KindUint64:
+		return []attribute.KeyValue{attribute.Int64(key, int64(value.Uint64()))}
+	case slog.KindFloat64:
+		return []attribute.KeyValue{attribute.Float64(key, value.Float64())}
+	case slog.KindBool:
+		return []attribute.KeyValue{attribute.Bool(key, value.Bool())}
+	case slog.KindTime:
+		return []attribute.KeyValue{attribute.String(key, value.Time().Format(time.RFC3339Nano))}
+	case slog.KindDuration:
+		return []attribute.KeyValue{attribute.String(key, value.Duration().String())}
+	case slog.KindLogValuer:
+		return keyValues(key, value.Resolve())
+	case slog.KindGroup:
+		attrs := []attribute.KeyValue{}
+		for _, attr := range value.Group() {
+			attrs = append(attrs, keyValues(key+"."+attr.Key, attr.Value)...)
+		}
+
+		return attrs
+	case slog.KindAny:
+		return anyKeyValues(key, value)
+	}
+
+	return []attribute.KeyValue{attribute.String(key, value.String())}
+}
+
+func anyKeyValues(key string, value slog.Value) []attribute.KeyValue {
+	switch v := value.Any().(type) {
+	case []string:
+		return []attribute.KeyValue{attribute.StringSlice(key, v)}
+	case []int:
+		return []attribute.KeyValue{attribute.IntSlice(key, v)}
+	case []int64:
+		return []attribute.KeyValue{attribute.Int64Slice(key, v)}
+	case []float64:
+		return []attribute.KeyValue{attribute.Float64Slice(key, v)}
+	case []bool:
+		return []attribute.KeyValue{attribute.BoolSlice(key, v)}
+	}
+
+	return []attribute.KeyValue{attribute.String(key, value.String())}
+}
@@ -0,0 +1,37 @@
+package trace_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/o11y/trace"
+)
+
+func TestErrorAttributes(t *testing.T) {
+	err := errors.New("some error").
+		WithKind(errors.KindNotFound).
+		WithCode("ERR_SOME_ERROR").
+		WithAttrs(
+			errors.IntAttr("int", 1),
+			errors.BoolAttr("bool", true),
+			errors.DurationAttr("duration", time.Second),
+			errors.StringsAttr("strings", "a", "b"),
+			errors.GroupAttr("group", errors.FloatAttr("float", 1.5)),
+		)
+
+	require.Equal(t, []attribute.KeyValue{
+		attribute.String("error.code", "ERR_SOME_ERROR"),
+		attribute.String("error.kind", "NOT_FOUND"),
+		attribute.String("error.severity", "WARN"),
+		attribute.Bool("error.retryable", false),
+		attribute.Int64("int", 1),
+		attribute.Bool("bool", true),
+		attribute.String("duration", "1s"),
+		attribute.StringSlice("strings", []string{"a", "b"}),
+		attribute.Float64("group.float", 1.5),
+	}, trace.ErrorAttributes(err))
+}
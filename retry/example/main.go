@@ -0,0 +1,29 @@
+package sample
+
+import (
+	"context"
+	"time"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/retry"
+)
+
+// For more examples on how to use this package, please read the tests.
+func main() {
+	ctx := context.Background()
+
+	// retries while the returned error is retryable, up to 3 attempts by default
+	_ = retry.Do(ctx, func(ctx context.Context) error {
+		return errors.ErrRequestError
+	})
+
+	// customizing the backoff policy
+	_ = retry.Do(ctx, func(ctx context.Context) error {
+		return nil
+	},
+		retry.WithName("fetch-account"),
+		retry.WithMaxAttempts(5),
+		retry.WithInitialInterval(200*time.Millisecond),
+		retry.WithMaxElapsedTime(10*time.Second),
+	)
+}
@@ -0,0 +1,31 @@
+module github.com/lcnascimento/go-kit/retry
+
+go 1.26.4
+
+replace github.com/lcnascimento/go-kit/errors => ../errors
+
+replace github.com/lcnascimento/go-kit/o11y => ../o11y
+
+replace github.com/lcnascimento/go-kit/env => ../env
+
+require (
+	github.com/lcnascimento/go-kit/errors v0.0.0-00010101000000-000000000000
+	github.com/lcnascimento/go-kit/o11y v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.11.1
+	go.opentelemetry.io/otel v1.44.1-0.20260626205805-41ff5ed18bec
+	go.opentelemetry.io/otel/trace v1.44.1-0.20260625150014-c84013202f01
+)
+
+require (
+	github.com/caarlos0/env/v10 v10.0.0 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/lcnascimento/go-kit/env v0.0.0-00010101000000-000000000000 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.44.1-0.20260625150014-c84013202f01 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,72 @@
+package retry
+
+import "time"
+
+type Option func(*config)
+
+// WithName identifies the retried operation in span events and metrics.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithMaxAttempts sets the maximum number of attempts, including the first one. Zero means no limit.
+func WithMaxAttempts(attempts int) Option {
+	return func(c *config) {
+		c.maxAttempts = attempts
+	}
+}
+
+// WithInitialInterval sets the delay applied after the first failed attempt.
+func WithInitialInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.initialInterval = interval
+	}
+}
+
+// WithMaxInterval caps the delay applied between attempts.
+func WithMaxInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.maxInterval = interval
+	}
+}
+
+// WithMaxElapsedTime sets the maximum time spent retrying, counted from the first attempt. Zero means no limit.
+func WithMaxElapsedTime(elapsed time.Duration) Option {
+	return func(c *config) {
+		c.maxElapsedTime = elapsed
+	}
+}
+
+// WithMultiplier sets the factor by which the delay grows after each failed attempt.
+func WithMultiplier(multiplier float64) Option {
+	return func(c *config) {
+		c.multiplier = multiplier
+	}
+}
+
+// WithJitter sets the randomization factor applied to each delay, between 0 and 1.
+// A factor of 0.5 spreads a delay of 1s between 500ms and 1.5s. Zero disables jitter.
+// Factors out of that range are clamped into it.
+func WithJitter(jitter float64) Option {
+	return func(c *config) {
+		c.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// WithRetryIf replaces the decision of whether an error should be retried. See [IsRetryable] for the default one.
+// Cancellations are never retried.
+func WithRetryIf(fn func(err error) bool) Option {
+	return func(c *config) {
+		c.shouldRetry = fn
+	}
+}
+
+// WithDelay overrides the delay applied before the next attempt. fn receives the error returned by the last
+// attempt and the delay computed by the backoff policy, e.g. to honour a delay suggested by the failed operation.
+func WithDelay(fn func(err error, backoff time.Duration) time.Duration) Option {
+	return func(c *config) {
+		c.delay = fn
+	}
+}
@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lcnascimento/go-kit/errors"
+)
+
+const (
+	defaultMaxAttempts     = 3
+	defaultInitialInterval = 100 * time.Millisecond
+	defaultMaxInterval     = 10 * time.Second
+	defaultMaxElapsedTime  = time.Minute
+	defaultMultiplier      = 2.0
+	defaultJitter          = 0.5
+)
+
+// Func is an operation that may be executed multiple times by [Do].
+type Func func(ctx context.Context) error
+
+type config struct {
+	name            string
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxElapsedTime  time.Duration
+	multiplier      float64
+	jitter          float64
+	shouldRetry     func(err error) bool
+	delay           func(err error, backoff time.Duration) time.Duration
+}
+
+// Do executes fn until it succeeds, returns a non retryable error or the retry budget is exhausted.
+// Attempts are spaced using exponential backoff with jitter.
+//
+// By default, an error is retried when it is tagged as retryable (see [errors.IsRetryable]) and its Kind does not
+// point to a permanent failure, like KindInvalidInput or KindNotFound. See [WithRetryIf] to change this decision.
+// Cancellations, either from ctx or errors matching [errors.ErrContextCanceled], stop the retries immediately.
+//
+// The error returned by the last attempt is returned untouched, unless ctx is done while waiting for the
+// next attempt, which results in [errors.ErrContextCanceled].
+func Do(ctx context.Context, fn Func, opts ...Option) error {
+	cfg := newConfig(opts...)
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			onAttempt(ctx, cfg.name, attempt, outcomeSuccess, nil, 0)
+			return nil
+		}
+
+		if ctx.Err() != nil || isCanceled(err) || !cfg.shouldRetry(err) {
+			onAttempt(ctx, cfg.name, attempt, outcomeFailure, err, 0)
+			return err
+		}
+
+		delay := cfg.backoff(attempt)
+		if cfg.delay != nil {
+			delay = cfg.delay(err, delay)
+		}
+
+		exhausted := cfg.maxAttempts > 0 && attempt >= cfg.maxAttempts
+		expired := cfg.maxElapsedTime > 0 && time.Since(start)+delay > cfg.maxElapsedTime
+
+		if exhausted || expired {
+			onAttempt(ctx, cfg.name, attempt, outcomeExhausted, err, 0)
+			return err
+		}
+
+		onAttempt(ctx, cfg.name, attempt, outcomeRetry, err, delay)
+
+		if err := wait(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// ShouldRetry returns the decision of whether an error should be retried configured by opts, which is the one
+// given by the last [WithRetryIf] option, or [IsRetryable] by default. It allows building a decision on top of
+// the configured one.
+func ShouldRetry(opts ...Option) func(err error) bool {
+	return newConfig(opts...).shouldRetry
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		maxAttempts:     defaultMaxAttempts,
+		initialInterval: defaultInitialInterval,
+		maxInterval:     defaultMaxInterval,
+		maxElapsedTime:  defaultMaxElapsedTime,
+		multiplier:      defaultMultiplier,
+		jitter:          defaultJitter,
+		shouldRetry:     IsRetryable,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// IsRetryable is the default decision used by [Do] to check whether an error should be retried.
+// It reports true for retryable errors whose Kind does not point to a permanent failure.
+func IsRetryable(err error) bool {
+	if !errors.IsRetryable(err) {
+		return false
+	}
+
+	switch errors.Kind(err) {
+	case errors.KindInvalidInput,
+		errors.KindUnauthenticated,
+		errors.KindUnauthorized,
+		errors.KindNotFound,
+		errors.KindConflict,
+		errors.KindUnprocessable,
+		errors.KindCanceled,
+		errors.KindFatal:
+		return false
+	}
+
+	return true
+}
+
+func isCanceled(err error) bool {
+	return errors.Is(err, errors.ErrContextCanceled) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// backoff returns the delay to be applied after the given attempt.
+func (c *config) backoff(attempt int) time.Duration {
+	interval := float64(c.initialInterval) * math.Pow(c.multiplier, float64(attempt-1))
+	if interval > float64(c.maxInterval) {
+		interval = float64(c.maxInterval)
+	}
+
+	if c.jitter > 0 {
+		delta := c.jitter * interval
+		//nolint:gosec // jitter does not need a cryptographically secure random number.
+		interval = interval - delta + rand.Float64()*(2*delta)
+	}
+
+	return time.Duration(interval)
+}
+
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.ErrContextCanceled
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/retry"
+)
+
+var (
+	errRetryable = errors.New("retryable error").WithKind(errors.KindServiceUnavailable).Retryable()
+	errPermanent = errors.New("permanent error").WithKind(errors.KindInternal)
+)
+
+func TestDo(t *testing.T) {
+	fastOpts := []retry.Option{
+		retry.WithInitialInterval(time.Millisecond),
+		retry.WithMaxInterval(time.Millisecond),
+	}
+
+	tt := []struct {
+		desc     string
+		errs     []error
+		opts     []retry.Option
+		err      error
+		attempts int
+	}{
+		{
+			desc:     "should not retry on success",
+			errs:     []error{nil},
+			attempts: 1,
+		},
+		{
+			desc:     "should retry until success",
+			errs:     []error{errRetryable, errRetryable, nil},
+			attempts: 3,
+		},
+		{
+			desc:     "should stop on non retryable errors",
+			errs:     []error{errPermanent},
+			err:      errPermanent,
+			attempts: 1,
+		},
+		{
+			desc:     "should stop on retryable errors with a permanent kind",
+			errs:     []error{errors.New("not found").WithKind(errors.KindNotFound).Retryable()},
+			err:      errors.New("not found").WithKind(errors.KindNotFound).Retryable(),
+			attempts: 1,
+		},
+		{
+			desc:     "should stop on context canceled errors",
+			errs:     []error{errors.ErrContextCanceled},
+			err:      errors.ErrContextCanceled,
+			attempts: 1,
+		},
+		{
+			desc:     "should stop when max attempts is reached",
+			errs:     []error{errRetryable, errRetryable, errRetryable, nil},
+			err:      errRetryable,
+			attempts: 3,
+		},
+		{
+			desc:     "should respect custom max attempts",
+			errs:     []error{errRetryable, errRetryable, nil},
+			opts:     []retry.Option{retry.WithMaxAttempts(2)},
+			err:      errRetryable,
+			attempts: 2,
+		},
+		{
+			desc: "should stop when max elapsed time is reached",
+			errs: []error{errRetryable, nil},
+			opts: []retry.Option{
+				retry.WithInitialInterval(time.Second),
+				retry.WithMaxInterval(time.Second),
+				retry.WithMaxElapsedTime(time.Millisecond),
+			},
+			err:      errRetryable,
+			attempts: 1,
+		},
+		{
+			desc: "should respect custom delay",
+			errs: []error{errRetryable, nil},
+			opts: []retry.Option{
+				retry.WithMaxElapsedTime(time.Second),
+				retry.WithDelay(func(error, time.Duration) time.Duration { return time.Hour }),
+			},
+			err:      errRetryable,
+			attempts: 1,
+		},
+		{
+			desc:     "should respect custom retry decision",
+			errs:     []error{errPermanent, nil},
+			opts:     []retry.Option{retry.WithRetryIf(func(error) bool { return true })},
+			attempts: 2,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			attempts := 0
+
+			fn := func(context.Context) error {
+				err := tc.errs[attempts]
+				attempts++
+
+				return err
+			}
+
+			err := retry.Do(context.Background(), fn, append(fastOpts, tc.opts...)...)
+
+			assert.Equal(t, tc.attempts, attempts)
+			if tc.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.err))
+			}
+		})
+	}
+}
+
+func TestDoContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	fn := func(context.Context) error {
+		attempts++
+		cancel()
+
+		return errRetryable
+	}
+
+	err := retry.Do(ctx, fn, retry.WithInitialInterval(time.Minute))
+
+	assert.Equal(t, 1, attempts)
+	assert.True(t, errors.Is(err, errRetryable))
+}
+
+func TestDoJitter(t *testing.T) {
+	var backoffs []time.Duration
+
+	fn := func(context.Context) error { return errRetryable }
+
+	_ = retry.Do(context.Background(), fn,
+		retry.WithMaxAttempts(50),
+		retry.WithInitialInterval(10*time.Millisecond),
+		retry.WithMaxInterval(10*time.Millisecond),
+		retry.WithJitter(5),
+		retry.WithDelay(func(_ error, backoff time.Duration) time.Duration {
+			backoffs = append(backoffs, backoff)
+			return 0
+		}),
+	)
+
+	assert.NotEmpty(t, backoffs)
+
+	for _, backoff := range backoffs {
+		assert.GreaterOrEqual(t, backoff, time.Duration(0), "should clamp the jitter factor")
+		assert.LessOrEqual(t, backoff, 20*time.Millisecond, "should clamp the jitter factor")
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	assert.True(t, retry.ShouldRetry()(errRetryable), "should default to IsRetryable")
+	assert.False(t, retry.ShouldRetry()(errPermanent), "should default to IsRetryable")
+
+	always := retry.ShouldRetry(retry.WithMaxAttempts(2), retry.WithRetryIf(func(error) bool { return true }))
+	assert.True(t, always(errPermanent), "should return the configured decision")
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, retry.IsRetryable(errRetryable))
+	assert.True(t, retry.IsRetryable(errors.ErrRequestError))
+	assert.False(t, retry.IsRetryable(errPermanent))
+	assert.False(t, retry.IsRetryable(errors.ErrContextCanceled))
+	assert.False(t, retry.IsRetryable(errors.New("invalid").WithKind(errors.KindInvalidInput).Retryable()))
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lcnascimento/go-kit/errors"
+	"github.com/lcnascimento/go-kit/o11y/metric"
+)
+
+const (
+	outcomeSuccess   = "success"
+	outcomeRetry     = "retry"
+	outcomeFailure   = "failure"
+	outcomeExhausted = "exhausted"
+)
+
+var (
+	pkg = "github.com/lcnascimento/go-kit/retry"
+
+	meter = otel.Meter(pkg)
+
+	attemptsCounter = metric.MustIntCounter(meter, "retry.attempt.total", "Number of attempts executed by retry.Do")
+)
+
+func onAttempt(ctx context.Context, name string, attempt int, outcome string, err error, delay time.Duration) {
+	attrs := []attribute.KeyValue{
+		attribute.String("retry.outcome", outcome),
+	}
+
+	if name != "" {
+		attrs = append(attrs, attribute.String("retry.operation", name))
+	}
+
+	attemptsCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs = append(attrs, attribute.Int("retry.attempt", attempt))
+
+	if err != nil {
+		attrs = append(attrs,
+			attribute.String("error.code", string(errors.Code(err))),
+			attribute.String("error.kind", string(errors.Kind(err))),
+			attribute.String("error.message", err.Error()),
+		)
+	}
+
+	if delay > 0 {
+		attrs = append(attrs, attribute.Int64("retry.delay_ms", delay.Milliseconds()))
+	}
+
+	span.AddEvent("retry.attempt", trace.WithAttributes(attrs...))
+}
@@ -15,6 +15,9 @@ type failureConverter struct {
 	converter *temporal.DefaultFailureConverter
 }
 
+// NewFailureConverter creates a failure converter that carries CustomErrors across workflows and activities.
+// Their failures hold the kind, the reasons and the canonical JSON wire format of the whole error tree as details,
+// the JSON wire format being decoded back when present. See [errors.FromJSON].
 func NewFailureConverter() converter.FailureConverter {
 	return &failureConverter{
 		converter: temporal.NewDefaultFailureConverter(temporal.DefaultFailureConverterOptions{
@@ -45,6 +48,7 @@ func (f *failureConverter) ErrorToFailure(err error) *failurepb.Failure {
 		Details: []any{
 			kind,
 			reasons,
+			errors.ToProto(err),
 		},
 	})
 
@@ -57,6 +61,13 @@ func (f *failureConverter) FailureToError(failure *failurepb.Failure) error {
 		return f.converter.FailureToError(failure)
 	}
 
+	if payloads := info.GetDetails().GetPayloads(); len(payloads) > 2 {
+		if decoded, err := errors.FromJSON(payloads[2].GetData()); err == nil {
+			return decoded
+		}
+	}
+
+	// failures without the wire format are rebuilt from the kind and the reasons of their details.
 	err := errors.New("%s", failure.GetMessage())
 
 	if info.Type != "" {